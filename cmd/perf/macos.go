@@ -3,11 +3,18 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
+
+	"howett.net/plist"
 )
 
 var (
@@ -21,11 +28,152 @@ var (
 	ErrLineNotFound = errors.New("line with prefix not found")
 )
 
+// plistTerminator separates successive plist documents in a streaming
+// `powermetrics --format plist` output.
+const plistTerminator = 0x00
+
 type GPUUtilisation struct {
 	ActiveFrequency uint64
 	ActiveResidency float64
 	IdleResidency   float64
 	Power           float64
+
+	// PStateResidency maps each GPU P-state's frequency, in Hz, to the
+	// fraction of the sample spent resident in it.
+	PStateResidency map[string]float64
+
+	// DVFMStateResidency maps each DVFM (dynamic voltage/frequency
+	// management) state name to the fraction of the sample spent resident
+	// in it.
+	DVFMStateResidency map[string]float64
+
+	// DCSAccumulator is the raw display co-processor subsystem accumulator
+	// value reported by powermetrics.
+	DCSAccumulator uint64
+
+	// ANEPower, ECorePower and PCorePower are only populated when the
+	// corresponding --samplers are enabled on the monitor.
+	ANEPower        float64
+	ECorePower      float64
+	PCorePower      float64
+	ThermalPressure string
+}
+
+// powermetricsSample models the subset of the `powermetrics --format plist`
+// schema this package consumes.
+type powermetricsSample struct {
+	GPU struct {
+		FreqHz          uint64  `plist:"freq_hz"`
+		ActiveResidency float64 `plist:"active_residency"`
+		IdleResidency   float64 `plist:"idle_residency"`
+		Power           float64 `plist:"gpu_energy"`
+		PStates         []struct {
+			FreqHz    uint64  `plist:"freq_hz"`
+			Residency float64 `plist:"residency"`
+		} `plist:"p_states"`
+	} `plist:"gpu"`
+
+	DVFMStates []struct {
+		Name      string  `plist:"name"`
+		Residency float64 `plist:"residency"`
+	} `plist:"dvfm_states"`
+
+	DCSAccumulator uint64 `plist:"dcs_accumulator"`
+
+	ANEPower   float64 `plist:"ane_energy"`
+	ECorePower float64 `plist:"ecpu_power"`
+	PCorePower float64 `plist:"pcpu_power"`
+
+	ThermalPressure string `plist:"thermal_pressure"`
+}
+
+func (s powermetricsSample) toGPUUtilisation() GPUUtilisation {
+	utilisation := GPUUtilisation{
+		ActiveFrequency:    s.GPU.FreqHz,
+		ActiveResidency:    s.GPU.ActiveResidency,
+		IdleResidency:      s.GPU.IdleResidency,
+		Power:              s.GPU.Power,
+		DCSAccumulator:     s.DCSAccumulator,
+		ANEPower:           s.ANEPower,
+		ECorePower:         s.ECorePower,
+		PCorePower:         s.PCorePower,
+		ThermalPressure:    s.ThermalPressure,
+		PStateResidency:    make(map[string]float64, len(s.GPU.PStates)),
+		DVFMStateResidency: make(map[string]float64, len(s.DVFMStates)),
+	}
+
+	for _, pState := range s.GPU.PStates {
+		utilisation.PStateResidency[strconv.FormatUint(pState.FreqHz, 10)] = pState.Residency
+	}
+
+	for _, dvfmState := range s.DVFMStates {
+		utilisation.DVFMStateResidency[dvfmState.Name] = dvfmState.Residency
+	}
+
+	return utilisation
+}
+
+// NewGPUMonitor starts a long-running `powermetrics --format plist` process
+// sampling at interval, and returns a channel of parsed GPUUtilisation
+// samples. The returned channel is closed once ctx is cancelled or the
+// powermetrics process exits. samplers additionally enables the cpu_power,
+// ane_power and/or thermal samplers alongside gpu_power, populating the
+// corresponding GPUUtilisation fields.
+//
+// This supersedes the per-tick GetGPUUtilisation process spawn: a single
+// powermetrics process keeps running and streams samples, avoiding both the
+// spawn overhead and the racey text scraping below. GetGPUUtilisation
+// remains available as a fallback for macOS versions where plist streaming
+// is unavailable.
+func NewGPUMonitor(ctx context.Context, interval time.Duration, samplers ...string) (<-chan GPUUtilisation, error) {
+	args := []string{
+		"--format", "plist",
+		"-i", strconv.FormatInt(interval.Milliseconds(), 10),
+		"--samplers", strings.Join(append([]string{"gpu_power"}, samplers...), ","),
+	}
+
+	powermetricsCmd := exec.CommandContext(ctx, "/usr/bin/powermetrics", args...)
+
+	stdout, err := powermetricsCmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open powermetrics stdout: %w", err)
+	}
+
+	if err := powermetricsCmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start powermetrics: %w", err)
+	}
+
+	samples := make(chan GPUUtilisation)
+
+	go func() {
+		defer close(samples)
+		defer powermetricsCmd.Wait()
+
+		reader := bufio.NewReader(stdout)
+
+		for {
+			chunk, err := reader.ReadBytes(plistTerminator)
+			if err != nil {
+				return
+			}
+
+			chunk = bytes.TrimRight(chunk, "\x00")
+
+			var sample powermetricsSample
+
+			if _, err := plist.Unmarshal(chunk, &sample); err != nil {
+				continue
+			}
+
+			select {
+			case samples <- sample.toGPUUtilisation():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return samples, nil
 }
 
 // IsRoot checks whether the current user is root.
@@ -261,32 +261,45 @@ func reportCPUUsage(
 		errg.Go(func() error {
 			metricMetadata := metricMetadata.SubMetric("gpu", metricMetadata.Tags)
 
-			intervalWait := time.After(0)
+			// Stream samples from a single long-running powermetrics process
+			// rather than spawning one per tick, and ask it to additionally
+			// report CPU core power, ANE power and thermal pressure.
+			samples, err := NewGPUMonitor(ctx, interval, "cpu_power", "ane_power", "thermal")
+			if err != nil {
+				return fmt.Errorf(
+					"cmd/cpu: failed to start GPU monitor: %w",
+					err,
+				)
+			}
 
 			for {
+				var gpuUtilisation GPUUtilisation
+
 				select {
 				case <-ctx.Done():
 					return fmt.Errorf(
 						"cmd/cpu: context cancelled while reporting CPU counts: %w",
 						ctx.Err(),
 					)
-				case <-intervalWait:
-					intervalWait = time.After(interval)
-				}
+				case sample, ok := <-samples:
+					if !ok {
+						return fmt.Errorf("cmd/cpu: GPU monitor stopped unexpectedly")
+					}
 
-				gpuUtilisation, err := GetGPUUtilisation()
-				if err != nil {
-					return fmt.Errorf(
-						"cmd/cpu: failed to get GPU utilisation: %w",
-						err,
-					)
+					gpuUtilisation = sample
 				}
 
+				timestamp := time.Now()
+
 				metrics := map[string]int{
 					"active_frequency": int(gpuUtilisation.ActiveFrequency),
 					"active_residency": int(gpuUtilisation.ActiveResidency),
 					"idle_residency":   int(gpuUtilisation.IdleResidency),
 					"power":            int(gpuUtilisation.Power),
+					"dcs_accumulator":  int(gpuUtilisation.DCSAccumulator),
+					"ane_power":        int(gpuUtilisation.ANEPower),
+					"ecore_power":      int(gpuUtilisation.ECorePower),
+					"pcore_power":      int(gpuUtilisation.PCorePower),
 				}
 
 				for metricName, metricValue := range metrics {
@@ -296,7 +309,7 @@ func reportCPUUsage(
 						ctx,
 						*metric,
 						fmt.Sprint(metricValue),
-						time.Now(),
+						timestamp,
 					); err != nil {
 						return fmt.Errorf(
 							"cmd/cpu: failed to queue GPU metric: %w",
@@ -304,6 +317,66 @@ func reportCPUUsage(
 						)
 					}
 				}
+
+				pStateResidency := metricMetadata.SubMetric("pstate_residency", metricMetadata.Tags)
+
+				for freq, residency := range gpuUtilisation.PStateResidency {
+					metric := pStateResidency.SubMetric(freq, pStateResidency.Tags)
+
+					if err := client.SendMetric(
+						ctx,
+						*metric,
+						fmt.Sprint(int(residency)),
+						timestamp,
+					); err != nil {
+						return fmt.Errorf(
+							"cmd/cpu: failed to queue GPU P-state metric: %w",
+							err,
+						)
+					}
+				}
+
+				dvfmResidency := metricMetadata.SubMetric("dvfm_residency", metricMetadata.Tags)
+
+				for name, residency := range gpuUtilisation.DVFMStateResidency {
+					metric := dvfmResidency.SubMetric(name, dvfmResidency.Tags)
+
+					if err := client.SendMetric(
+						ctx,
+						*metric,
+						fmt.Sprint(int(residency)),
+						timestamp,
+					); err != nil {
+						return fmt.Errorf(
+							"cmd/cpu: failed to queue GPU DVFM state metric: %w",
+							err,
+						)
+					}
+				}
+
+				if gpuUtilisation.ThermalPressure != "" {
+					thermalTags := make(map[string]string, len(metricMetadata.Tags)+1)
+
+					for tag, value := range metricMetadata.Tags {
+						thermalTags[tag] = value
+					}
+
+					thermalTags["state"] = gpuUtilisation.ThermalPressure
+
+					metric := metricMetadata.SubMetric("thermal_pressure", thermalTags)
+
+					if err := client.SendMetric(
+						ctx,
+						*metric,
+						"1",
+						timestamp,
+					); err != nil {
+						return fmt.Errorf(
+							"cmd/cpu: failed to queue GPU thermal pressure metric: %w",
+							err,
+						)
+					}
+				}
 			}
 		})
 	} else {
@@ -0,0 +1,59 @@
+// Package collectors provides a pluggable metric-collection framework,
+// inspired by cc-metric-collector: a set of Collector implementations that
+// are configured from JSON and scheduled by a Manager, either concurrently
+// or serially depending on what each collector can tolerate.
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+// MetricSink is the narrow interface a Collector needs in order to emit
+// metrics. It is satisfied by graphite.Client.
+type MetricSink interface {
+	// SendMetric sends a single metric sample.
+	SendMetric(ctx context.Context,
+		metric graphite.MetricMetadata,
+		value string,
+		timestamp time.Time,
+	) error
+}
+
+// Collector gathers a single kind of metric on a schedule controlled by a
+// Manager.
+type Collector interface {
+	// Init configures the collector from its JSON configuration block, as
+	// found in the "config" field of its entry in the Manager config.
+	Init(config json.RawMessage) error
+
+	// Read gathers one sample and sends it to sink. interval is the
+	// collector's configured read interval, which collectors that report
+	// rates from cumulative counters need in order to normalise values.
+	Read(ctx context.Context, interval time.Duration, sink MetricSink) error
+
+	// Close releases any resources held by the collector.
+	Close()
+
+	// Parallel reports whether Read may safely run concurrently with other
+	// collectors. Collectors that shell out to an exclusive system tool
+	// (e.g. powermetrics) must return false, and are scheduled serially by
+	// the Manager instead.
+	Parallel() bool
+}
+
+// registry maps a collector's configured type name to a factory function.
+// Platform-specific collectors register themselves from an init() in their
+// own (possibly build-tagged) file.
+var registry = map[string]func() Collector{
+	"cpustat":      func() Collector { return &CpustatCollector{} },
+	"gopsutil_cpu": func() Collector { return &GopsutilCPUCollector{} },
+	"memory":       func() Collector { return &MemoryCollector{} },
+	"disk":         func() Collector { return &DiskCollector{} },
+	"network":      func() Collector { return &NetworkCollector{} },
+	"load":         func() Collector { return &LoadCollector{} },
+	"temperature":  func() Collector { return &TemperatureCollector{} },
+}
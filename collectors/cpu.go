@@ -0,0 +1,86 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	cpustats "github.com/mackerelio/go-osstat/cpu"
+)
+
+// CpustatCollector reports incremental system-wide CPU time counters using
+// github.com/mackerelio/go-osstat/cpu. It was previously hard-coded into
+// cmd/cpu.
+type CpustatCollector struct {
+	prefix   []string
+	previous *cpustats.Stats
+}
+
+type cpustatConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+// Init implements Collector.
+func (c *CpustatCollector) Init(config json.RawMessage) error {
+	var cfg cpustatConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse cpustat config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"cpustat"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector. The first Read after Init only seeds the
+// previous sample, since there is nothing yet to compute a delta against.
+func (c *CpustatCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	stats, err := cpustats.Get()
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get CPU stats: %w", err)
+	}
+
+	if c.previous == nil {
+		c.previous = stats
+		return nil
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+
+	values := map[string]int{
+		"idle":   int(stats.Idle - c.previous.Idle),
+		"nice":   int(stats.Nice - c.previous.Nice),
+		"system": int(stats.System - c.previous.System),
+		"total":  int(stats.Total - c.previous.Total),
+		"user":   int(stats.User - c.previous.User),
+	}
+
+	timestamp := time.Now()
+
+	for name, value := range values {
+		metric := metadata.SubMetric(name, metadata.Tags)
+
+		if err := sink.SendMetric(ctx, *metric, fmt.Sprint(value), timestamp); err != nil {
+			return fmt.Errorf("collectors: failed to send cpustat metric: %w", err)
+		}
+	}
+
+	c.previous = stats
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *CpustatCollector) Close() {}
+
+// Parallel implements Collector.
+func (c *CpustatCollector) Parallel() bool { return true }
@@ -0,0 +1,92 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskCollector reports incremental disk I/O counters per device using
+// gopsutil.
+type DiskCollector struct {
+	prefix   []string
+	previous map[string]disk.IOCountersStat
+}
+
+type diskConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+// Init implements Collector.
+func (c *DiskCollector) Init(config json.RawMessage) error {
+	var cfg diskConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse disk config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"disk"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector. The first Read after Init only seeds the
+// previous sample, since there is nothing yet to compute a delta against.
+func (c *DiskCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	counters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get disk I/O counters: %w", err)
+	}
+
+	if c.previous == nil {
+		c.previous = counters
+		return nil
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+	timestamp := time.Now()
+
+	for name, counter := range counters {
+		previous, ok := c.previous[name]
+		if !ok {
+			continue
+		}
+
+		values := map[string]uint64{
+			"read_bytes":  counter.ReadBytes - previous.ReadBytes,
+			"write_bytes": counter.WriteBytes - previous.WriteBytes,
+			"read_count":  counter.ReadCount - previous.ReadCount,
+			"write_count": counter.WriteCount - previous.WriteCount,
+		}
+
+		diskMetadata := metadata.SubMetric(name, metadata.Tags)
+
+		for metricName, value := range values {
+			metric := diskMetadata.SubMetric(metricName, diskMetadata.Tags)
+
+			if err := sink.SendMetric(ctx, *metric, fmt.Sprint(value), timestamp); err != nil {
+				return fmt.Errorf("collectors: failed to send disk metric: %w", err)
+			}
+		}
+	}
+
+	c.previous = counters
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *DiskCollector) Close() {}
+
+// Parallel implements Collector.
+func (c *DiskCollector) Parallel() bool { return true }
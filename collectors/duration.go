@@ -0,0 +1,37 @@
+package collectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration wraps time.Duration so collector intervals can be configured
+// either as a Go duration string (e.g. "5s") or as a plain number of
+// nanoseconds in JSON.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("collectors: failed to parse duration: %w", err)
+	}
+
+	switch value := raw.(type) {
+	case float64:
+		*d = Duration(time.Duration(value))
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("collectors: invalid duration %q: %w", value, err)
+		}
+
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("collectors: invalid duration: %v", raw)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+//go:build darwin
+
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+var (
+	powermetricsCmd = []string{
+		"/usr/bin/powermetrics",
+		"--samplers=gpu_power",
+		"--sample-rate=500",
+		"--sample-count=1",
+	}
+
+	errLineNotFound = errors.New("collectors: line with prefix not found")
+)
+
+// gpuUtilisation mirrors the fields scraped from a single powermetrics
+// gpu_power sample, as previously hard-coded into cmd/perf.
+type gpuUtilisation struct {
+	ActiveFrequency uint64
+	ActiveResidency float64
+	IdleResidency   float64
+	Power           float64
+}
+
+// M1GPUCollector reports Apple Silicon GPU utilisation by shelling out to
+// powermetrics. Since powermetrics requires exclusive access to its sampling
+// facility, this collector must run serially.
+type M1GPUCollector struct {
+	prefix []string
+}
+
+type gpuConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+func init() {
+	registry["m1_gpu"] = func() Collector { return &M1GPUCollector{} }
+}
+
+// Init implements Collector.
+func (c *M1GPUCollector) Init(config json.RawMessage) error {
+	var cfg gpuConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse m1_gpu config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"gpu"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector.
+func (c *M1GPUCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	utilisation, err := getGPUUtilisation()
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get GPU utilisation: %w", err)
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+	timestamp := time.Now()
+
+	values := map[string]int{
+		"active_frequency": int(utilisation.ActiveFrequency),
+		"active_residency": int(utilisation.ActiveResidency),
+		"idle_residency":   int(utilisation.IdleResidency),
+		"power":            int(utilisation.Power),
+	}
+
+	for name, value := range values {
+		metric := metadata.SubMetric(name, metadata.Tags)
+
+		if err := sink.SendMetric(ctx, *metric, fmt.Sprint(value), timestamp); err != nil {
+			return fmt.Errorf("collectors: failed to send GPU metric: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *M1GPUCollector) Close() {}
+
+// Parallel implements Collector. powermetrics samplers are exclusive, so
+// this collector must be scheduled serially alongside any other
+// powermetrics-backed collector.
+func (c *M1GPUCollector) Parallel() bool { return false }
+
+// getGPUUtilisation shells out to powermetrics and parses its text output.
+func getGPUUtilisation() (*gpuUtilisation, error) {
+	cmdOutput, err := exec.Command(powermetricsCmd[0], powermetricsCmd[1:]...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(cmdOutput), "\n")
+
+	var utilisation gpuUtilisation
+
+	line, err := parseLine("GPU HW active frequency:", lines)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Sscanf(line, "%d MHz", &utilisation.ActiveFrequency)
+	if err == nil {
+		utilisation.ActiveFrequency *= 1_000_000
+	} else {
+		_, err = fmt.Sscanf(line, "%d GHz", &utilisation.ActiveFrequency)
+		if err != nil {
+			return nil, fmt.Errorf("collectors: failed to parse GPU HW active frequency: %w", err)
+		}
+
+		utilisation.ActiveFrequency *= 1_000_000_000
+	}
+
+	line, err = parseLine("GPU HW active residency:", lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = fmt.Sscanf(line, "%f%%", &utilisation.ActiveResidency); err != nil {
+		return nil, fmt.Errorf("collectors: failed to parse GPU HW active residency: %w", err)
+	}
+
+	line, err = parseLine("GPU idle residency:", lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = fmt.Sscanf(line, "%f%%", &utilisation.IdleResidency); err != nil {
+		return nil, fmt.Errorf("collectors: failed to parse GPU idle residency: %w", err)
+	}
+
+	line, err = parseLine("GPU Power:", lines)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Sscanf(line, "%f mW", &utilisation.Power)
+	if err == nil {
+		utilisation.Power /= 1_000
+	} else {
+		_, err = fmt.Sscanf(line, "%f W", &utilisation.Power)
+		if err != nil {
+			return nil, fmt.Errorf("collectors: failed to parse GPU Power: %w", err)
+		}
+	}
+
+	return &utilisation, nil
+}
+
+func parseLine(prefix string, lines []string) (string, error) {
+	for _, line := range lines {
+		if trimmedLine, found := strings.CutPrefix(line, prefix); found {
+			return trimmedLine, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", errLineNotFound, prefix)
+}
@@ -0,0 +1,72 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// LoadCollector reports the system load average using gopsutil.
+type LoadCollector struct {
+	prefix []string
+}
+
+type loadConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+// Init implements Collector.
+func (c *LoadCollector) Init(config json.RawMessage) error {
+	var cfg loadConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse load config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"load"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector.
+func (c *LoadCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	avg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get load average: %w", err)
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+	timestamp := time.Now()
+
+	values := map[string]float64{
+		"load1":  avg.Load1,
+		"load5":  avg.Load5,
+		"load15": avg.Load15,
+	}
+
+	for name, value := range values {
+		metric := metadata.SubMetric(name, metadata.Tags)
+
+		if err := sink.SendMetric(ctx, *metric, fmt.Sprintf("%.2f", value), timestamp); err != nil {
+			return fmt.Errorf("collectors: failed to send load metric: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *LoadCollector) Close() {}
+
+// Parallel implements Collector.
+func (c *LoadCollector) Parallel() bool { return true }
@@ -0,0 +1,212 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// CollectorConfig configures a single collector entry in a Manager Config.
+type CollectorConfig struct {
+	// Type selects the collector implementation, matching one of the names
+	// registered in the package's collector registry (e.g. "cpustat",
+	// "memory").
+	Type string `json:"type"`
+
+	// Enabled determines whether this collector is started. Disabled
+	// entries are skipped entirely.
+	Enabled bool `json:"enabled"`
+
+	// Interval overrides the Manager's default read interval for this
+	// collector. Zero means use the default.
+	Interval Duration `json:"interval"`
+
+	// ExcludeMetrics lists dotted metric names (e.g. "cpustat.idle") that
+	// should be dropped rather than sent to the sink.
+	ExcludeMetrics []string `json:"exclude_metrics"`
+
+	// Config is passed verbatim to the collector's Init method.
+	Config json.RawMessage `json:"config"`
+}
+
+// Config is the JSON configuration consumed by NewManager.
+type Config struct {
+	Collectors []CollectorConfig `json:"collectors"`
+}
+
+// LoadConfig decodes a Manager Config from r.
+func LoadConfig(r io.Reader) (Config, error) {
+	var config Config
+
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("collectors: failed to decode config: %w", err)
+	}
+
+	return config, nil
+}
+
+type managedCollector struct {
+	name      string
+	collector Collector
+	interval  time.Duration
+	sink      MetricSink
+}
+
+// Manager initialises and schedules a set of Collectors, running
+// parallelisable collectors concurrently and the rest on a single shared,
+// serial goroutine.
+type Manager struct {
+	collectors []managedCollector
+}
+
+// NewManager initialises one Collector per enabled entry in config,
+// defaulting each collector's read interval to defaultInterval. Metrics
+// emitted by a collector are sent to sink, filtered by that collector's
+// ExcludeMetrics.
+func NewManager(sink MetricSink, config Config, defaultInterval time.Duration) (*Manager, error) {
+	manager := &Manager{}
+
+	for _, entry := range config.Collectors {
+		if !entry.Enabled {
+			continue
+		}
+
+		factory, ok := registry[entry.Type]
+		if !ok {
+			return nil, fmt.Errorf("collectors: unknown collector type: %q", entry.Type)
+		}
+
+		collector := factory()
+
+		if err := collector.Init(entry.Config); err != nil {
+			return nil, fmt.Errorf(
+				"collectors: failed to initialise %q collector: %w",
+				entry.Type,
+				err,
+			)
+		}
+
+		interval := time.Duration(entry.Interval)
+		if interval == 0 {
+			interval = defaultInterval
+		}
+
+		manager.collectors = append(manager.collectors, managedCollector{
+			name:      entry.Type,
+			collector: collector,
+			interval:  interval,
+			sink:      newExcludingSink(sink, entry.ExcludeMetrics),
+		})
+	}
+
+	return manager, nil
+}
+
+// Close closes every collector the Manager initialised.
+func (m *Manager) Close() {
+	for _, mc := range m.collectors {
+		mc.collector.Close()
+	}
+}
+
+// Run schedules every collector until ctx is cancelled or a collector
+// returns an error. Collectors that report Parallel() run on their own
+// goroutine; the rest share a single goroutine that wakes at the shortest
+// of their configured intervals and reads each one at its own interval.
+func (m *Manager) Run(ctx context.Context) error {
+	errg, ctx := errgroup.WithContext(ctx)
+
+	var serial []managedCollector
+
+	for _, mc := range m.collectors {
+		mc := mc
+
+		if mc.collector.Parallel() {
+			errg.Go(func() error {
+				return runCollector(ctx, mc)
+			})
+		} else {
+			serial = append(serial, mc)
+		}
+	}
+
+	if len(serial) > 0 {
+		errg.Go(func() error {
+			return runSerial(ctx, serial)
+		})
+	}
+
+	return errg.Wait()
+}
+
+func runCollector(ctx context.Context, mc managedCollector) error {
+	ticker := time.NewTicker(mc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"collectors: context cancelled while running %q: %w",
+				mc.name,
+				ctx.Err(),
+			)
+		case <-ticker.C:
+			if err := mc.collector.Read(ctx, mc.interval, mc.sink); err != nil {
+				return fmt.Errorf("collectors: %q collector failed: %w", mc.name, err)
+			}
+		}
+	}
+}
+
+// runSerial shares a single goroutine between every non-parallelisable
+// collector, since some (e.g. powermetrics-backed collectors) can't tolerate
+// concurrent access to the same exclusive resource. It wakes at the
+// shortest configured interval, but only reads each collector once its own
+// interval has actually elapsed, so a 30s collector isn't read on every 1s
+// tick just because another serial collector is configured at 1s.
+func runSerial(ctx context.Context, collectors []managedCollector) error {
+	tick := collectors[0].interval
+
+	for _, mc := range collectors[1:] {
+		if mc.interval < tick {
+			tick = mc.interval
+		}
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	nextDue := make([]time.Time, len(collectors))
+	start := time.Now()
+
+	for i, mc := range collectors {
+		nextDue[i] = start.Add(mc.interval)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"collectors: context cancelled while running serial collectors: %w",
+				ctx.Err(),
+			)
+		case now := <-ticker.C:
+			for i, mc := range collectors {
+				if now.Before(nextDue[i]) {
+					continue
+				}
+
+				if err := mc.collector.Read(ctx, mc.interval, mc.sink); err != nil {
+					return fmt.Errorf("collectors: %q collector failed: %w", mc.name, err)
+				}
+
+				nextDue[i] = nextDue[i].Add(mc.interval)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// MemoryCollector reports virtual memory usage using gopsutil.
+type MemoryCollector struct {
+	prefix []string
+}
+
+type memoryConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+// Init implements Collector.
+func (c *MemoryCollector) Init(config json.RawMessage) error {
+	var cfg memoryConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse memory config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"memory"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector.
+func (c *MemoryCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	stats, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get memory stats: %w", err)
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+	timestamp := time.Now()
+
+	values := map[string]uint64{
+		"total":     stats.Total,
+		"available": stats.Available,
+		"used":      stats.Used,
+		"free":      stats.Free,
+		"cached":    stats.Cached,
+	}
+
+	for name, value := range values {
+		metric := metadata.SubMetric(name, metadata.Tags)
+
+		if err := sink.SendMetric(ctx, *metric, fmt.Sprint(value), timestamp); err != nil {
+			return fmt.Errorf("collectors: failed to send memory metric: %w", err)
+		}
+	}
+
+	usedPercentMetric := metadata.SubMetric("used_percent", metadata.Tags)
+
+	if err := sink.SendMetric(
+		ctx,
+		*usedPercentMetric,
+		fmt.Sprintf("%.2f", stats.UsedPercent),
+		timestamp,
+	); err != nil {
+		return fmt.Errorf("collectors: failed to send memory metric: %w", err)
+	}
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *MemoryCollector) Close() {}
+
+// Parallel implements Collector.
+func (c *MemoryCollector) Parallel() bool { return true }
@@ -0,0 +1,99 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// NetworkCollector reports incremental network I/O counters per interface
+// using gopsutil.
+type NetworkCollector struct {
+	prefix   []string
+	previous map[string]net.IOCountersStat
+}
+
+type networkConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+// Init implements Collector.
+func (c *NetworkCollector) Init(config json.RawMessage) error {
+	var cfg networkConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse network config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"network"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector. The first Read after Init only seeds the
+// previous sample, since there is nothing yet to compute a delta against.
+func (c *NetworkCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	counters, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get network I/O counters: %w", err)
+	}
+
+	current := make(map[string]net.IOCountersStat, len(counters))
+	for _, counter := range counters {
+		current[counter.Name] = counter
+	}
+
+	if c.previous == nil {
+		c.previous = current
+		return nil
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+	timestamp := time.Now()
+
+	for name, counter := range current {
+		previous, ok := c.previous[name]
+		if !ok {
+			continue
+		}
+
+		values := map[string]uint64{
+			"bytes_sent":   counter.BytesSent - previous.BytesSent,
+			"bytes_recv":   counter.BytesRecv - previous.BytesRecv,
+			"packets_sent": counter.PacketsSent - previous.PacketsSent,
+			"packets_recv": counter.PacketsRecv - previous.PacketsRecv,
+			"errin":        counter.Errin - previous.Errin,
+			"errout":       counter.Errout - previous.Errout,
+		}
+
+		netMetadata := metadata.SubMetric(name, metadata.Tags)
+
+		for metricName, value := range values {
+			metric := netMetadata.SubMetric(metricName, netMetadata.Tags)
+
+			if err := sink.SendMetric(ctx, *metric, fmt.Sprint(value), timestamp); err != nil {
+				return fmt.Errorf("collectors: failed to send network metric: %w", err)
+			}
+		}
+	}
+
+	c.previous = current
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *NetworkCollector) Close() {}
+
+// Parallel implements Collector.
+func (c *NetworkCollector) Parallel() bool { return true }
@@ -0,0 +1,47 @@
+package collectors
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+// excludingSink wraps a MetricSink, dropping any metric whose dotted name
+// appears in excluded.
+type excludingSink struct {
+	sink     MetricSink
+	excluded map[string]struct{}
+}
+
+// newExcludingSink wraps sink so that metrics named in excludeMetrics are
+// dropped before reaching it. If excludeMetrics is empty, sink is returned
+// unwrapped.
+func newExcludingSink(sink MetricSink, excludeMetrics []string) MetricSink {
+	if len(excludeMetrics) == 0 {
+		return sink
+	}
+
+	excluded := make(map[string]struct{}, len(excludeMetrics))
+
+	for _, name := range excludeMetrics {
+		excluded[name] = struct{}{}
+	}
+
+	return &excludingSink{sink: sink, excluded: excluded}
+}
+
+// SendMetric implements MetricSink.
+func (s *excludingSink) SendMetric(
+	ctx context.Context,
+	metric graphite.MetricMetadata,
+	value string,
+	timestamp time.Time,
+) error {
+	if _, ok := s.excluded[strings.Join(metric.Name, ".")]; ok {
+		return nil
+	}
+
+	return s.sink.SendMetric(ctx, metric, value, timestamp)
+}
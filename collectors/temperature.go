@@ -0,0 +1,72 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// TemperatureCollector reports sensor temperatures read from the platform's
+// sensor files (e.g. /sys/class/hwmon on Linux) via gopsutil.
+type TemperatureCollector struct {
+	prefix []string
+}
+
+type temperatureConfig struct {
+	Prefix []string `json:"prefix"`
+}
+
+// Init implements Collector.
+func (c *TemperatureCollector) Init(config json.RawMessage) error {
+	var cfg temperatureConfig
+
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return fmt.Errorf("collectors: failed to parse temperature config: %w", err)
+		}
+	}
+
+	if len(cfg.Prefix) == 0 {
+		cfg.Prefix = []string{"temperature"}
+	}
+
+	c.prefix = cfg.Prefix
+
+	return nil
+}
+
+// Read implements Collector.
+func (c *TemperatureCollector) Read(ctx context.Context, interval time.Duration, sink MetricSink) error {
+	sensors, err := host.SensorsTemperaturesWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("collectors: failed to get sensor temperatures: %w", err)
+	}
+
+	metadata := graphite.MetricMetadata{Name: c.prefix}
+	timestamp := time.Now()
+
+	for _, sensor := range sensors {
+		metric := metadata.SubMetric(sensor.SensorKey, metadata.Tags)
+
+		if err := sink.SendMetric(
+			ctx,
+			*metric,
+			fmt.Sprintf("%.2f", sensor.Temperature),
+			timestamp,
+		); err != nil {
+			return fmt.Errorf("collectors: failed to send temperature metric: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close implements Collector.
+func (c *TemperatureCollector) Close() {}
+
+// Parallel implements Collector.
+func (c *TemperatureCollector) Parallel() bool { return true }
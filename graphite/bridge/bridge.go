@@ -0,0 +1,321 @@
+// Package bridge mirrors the Prometheus client_golang Graphite bridge: it
+// periodically gathers metrics from a prometheus.Gatherer and translates
+// them into Graphite line-protocol writes via an existing graphite.Client.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DefaultInterval is the default interval between successive pushes in Run.
+const DefaultInterval = 15 * time.Second
+
+var (
+	// ErrNoGatherer is returned when no Prometheus gatherer is configured.
+	ErrNoGatherer = errors.New("graphite/bridge: no Prometheus gatherer configured")
+
+	// ErrNoClient is returned when no Graphite client is configured.
+	ErrNoClient = errors.New("graphite/bridge: no Graphite client configured")
+)
+
+// ErrorHandler decides what happens when a push fails while the bridge is
+// running in Run. Returning nil swallows the error and keeps the loop
+// running; returning an error aborts Run with that error.
+type ErrorHandler func(err error) error
+
+// LogAndContinue is an ErrorHandler that logs the error to the standard
+// logger and keeps the Run loop going.
+func LogAndContinue(err error) error {
+	log.Printf("graphite/bridge: push failed: %v", err)
+
+	return nil
+}
+
+// AbortOnError is an ErrorHandler that stops the Run loop by returning the
+// error unchanged.
+func AbortOnError(err error) error {
+	return err
+}
+
+// Sanitizer rewrites a Prometheus label name or value into one that is safe
+// to use as a Graphite tag.
+type Sanitizer func(string) string
+
+// DefaultSanitizer replaces the characters that would otherwise corrupt the
+// Graphite tag syntax (';', '=', spaces and newlines) with underscores.
+func DefaultSanitizer(value string) string {
+	return tagReplacer.Replace(value)
+}
+
+var tagReplacer = strings.NewReplacer(
+	";", "_",
+	"=", "_",
+	" ", "_",
+	"\n", "_",
+)
+
+// Config configures a Bridge.
+type Config struct {
+	// Gatherer is the Prometheus gatherer to collect metrics from.
+	Gatherer prometheus.Gatherer
+
+	// Client is the Graphite client metrics are pushed through. Push only
+	// queues metrics via [graphite.Client.SendMetric]; the caller is
+	// responsible for running [graphite.Client.Submit] to flush them, in
+	// the same way as the cmd/cpu collectors do.
+	Client graphite.Client
+
+	// Prefix is prepended to every metric name pushed to Graphite.
+	Prefix []string
+
+	// Tags are attached to every metric pushed to Graphite, in addition to
+	// the metric's own Prometheus labels.
+	Tags map[string]string
+
+	// Interval is the delay between successive pushes in Run. Defaults to
+	// DefaultInterval.
+	Interval time.Duration
+
+	// ErrorHandler decides whether Run continues or aborts after a failed
+	// push. Defaults to AbortOnError.
+	ErrorHandler ErrorHandler
+
+	// Sanitizer rewrites label names and values before they're used as
+	// Graphite tags. Defaults to DefaultSanitizer.
+	Sanitizer Sanitizer
+}
+
+// Bridge gathers metrics from a Prometheus gatherer and pushes them to a
+// Graphite server.
+type Bridge struct {
+	config Config
+}
+
+// NewBridge creates a new Bridge from the given Config, filling in defaults
+// for any unset fields.
+func NewBridge(config *Config) (*Bridge, error) {
+	if config.Gatherer == nil {
+		return nil, ErrNoGatherer
+	}
+
+	if config.Client == nil {
+		return nil, ErrNoClient
+	}
+
+	bridgeConfig := *config
+
+	if bridgeConfig.Interval == 0 {
+		bridgeConfig.Interval = DefaultInterval
+	}
+
+	if bridgeConfig.ErrorHandler == nil {
+		bridgeConfig.ErrorHandler = AbortOnError
+	}
+
+	if bridgeConfig.Sanitizer == nil {
+		bridgeConfig.Sanitizer = DefaultSanitizer
+	}
+
+	return &Bridge{config: bridgeConfig}, nil
+}
+
+// Push gathers metrics once and queues them on the configured Graphite
+// client via SendMetric.
+func (b *Bridge) Push(ctx context.Context) error {
+	metricFamilies, err := b.config.Gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("graphite/bridge: failed to gather metrics: %w", err)
+	}
+
+	timestamp := time.Now()
+
+	for _, metricFamily := range metricFamilies {
+		if err := b.pushFamily(ctx, metricFamily, timestamp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run gathers and pushes metrics on the configured interval until ctx is
+// cancelled or the error handler aborts the loop.
+func (b *Bridge) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("graphite/bridge: context cancelled: %w", ctx.Err())
+		case <-ticker.C:
+			if err := b.Push(ctx); err != nil {
+				if handlerErr := b.config.ErrorHandler(err); handlerErr != nil {
+					return handlerErr
+				}
+			}
+		}
+	}
+}
+
+func (b *Bridge) pushFamily(
+	ctx context.Context,
+	metricFamily *dto.MetricFamily,
+	timestamp time.Time,
+) error {
+	name := metricFamily.GetName()
+
+	for _, metric := range metricFamily.GetMetric() {
+		metadata := b.metadata(name, metric.GetLabel())
+
+		switch metricFamily.GetType() {
+		case dto.MetricType_COUNTER:
+			if err := b.send(ctx, metadata, metric.GetCounter().GetValue(), timestamp); err != nil {
+				return err
+			}
+		case dto.MetricType_GAUGE:
+			if err := b.send(ctx, metadata, metric.GetGauge().GetValue(), timestamp); err != nil {
+				return err
+			}
+		case dto.MetricType_UNTYPED:
+			if err := b.send(ctx, metadata, metric.GetUntyped().GetValue(), timestamp); err != nil {
+				return err
+			}
+		case dto.MetricType_HISTOGRAM:
+			if err := b.pushHistogram(ctx, metadata, metric.GetHistogram(), timestamp); err != nil {
+				return err
+			}
+		case dto.MetricType_SUMMARY:
+			if err := b.pushSummary(ctx, metadata, metric.GetSummary(), timestamp); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Bridge) pushHistogram(
+	ctx context.Context,
+	metadata graphite.MetricMetadata,
+	histogram *dto.Histogram,
+	timestamp time.Time,
+) error {
+	countMetadata := metadata.SubMetric("count", metadata.Tags)
+	if err := b.send(ctx, *countMetadata, float64(histogram.GetSampleCount()), timestamp); err != nil {
+		return err
+	}
+
+	sumMetadata := metadata.SubMetric("sum", metadata.Tags)
+	if err := b.send(ctx, *sumMetadata, histogram.GetSampleSum(), timestamp); err != nil {
+		return err
+	}
+
+	for _, bucket := range histogram.GetBucket() {
+		bucketTags := b.tagsWith(metadata.Tags, "le", formatFloat(bucket.GetUpperBound()))
+		bucketMetadata := metadata.SubMetric("bucket", bucketTags)
+
+		if err := b.send(ctx, *bucketMetadata, float64(bucket.GetCumulativeCount()), timestamp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Bridge) pushSummary(
+	ctx context.Context,
+	metadata graphite.MetricMetadata,
+	summary *dto.Summary,
+	timestamp time.Time,
+) error {
+	countMetadata := metadata.SubMetric("count", metadata.Tags)
+	if err := b.send(ctx, *countMetadata, float64(summary.GetSampleCount()), timestamp); err != nil {
+		return err
+	}
+
+	sumMetadata := metadata.SubMetric("sum", metadata.Tags)
+	if err := b.send(ctx, *sumMetadata, summary.GetSampleSum(), timestamp); err != nil {
+		return err
+	}
+
+	for _, quantile := range summary.GetQuantile() {
+		if math.IsNaN(quantile.GetValue()) {
+			continue
+		}
+
+		quantileTags := b.tagsWith(metadata.Tags, "quantile", formatFloat(quantile.GetQuantile()))
+		quantileMetadata := graphite.MetricMetadata{Name: metadata.Name, Tags: quantileTags}
+
+		if err := b.send(ctx, quantileMetadata, quantile.GetValue(), timestamp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Bridge) send(
+	ctx context.Context,
+	metadata graphite.MetricMetadata,
+	value float64,
+	timestamp time.Time,
+) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+
+	if err := b.config.Client.SendMetric(ctx, metadata, formatFloat(value), timestamp); err != nil {
+		return fmt.Errorf("graphite/bridge: failed to queue metric: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Bridge) metadata(name string, labels []*dto.LabelPair) graphite.MetricMetadata {
+	tags := make(map[string]string, len(labels)+len(b.config.Tags))
+
+	for tag, value := range b.config.Tags {
+		tags[tag] = value
+	}
+
+	for _, label := range labels {
+		tags[b.config.Sanitizer(label.GetName())] = b.config.Sanitizer(label.GetValue())
+	}
+
+	metricName := make([]string, 0, len(b.config.Prefix)+1)
+	metricName = append(metricName, b.config.Prefix...)
+	metricName = append(metricName, name)
+
+	return graphite.MetricMetadata{
+		Name: metricName,
+		Tags: tags,
+	}
+}
+
+func (b *Bridge) tagsWith(tags map[string]string, tag, value string) map[string]string {
+	newTags := make(map[string]string, len(tags)+1)
+
+	for k, v := range tags {
+		newTags[k] = v
+	}
+
+	newTags[tag] = value
+
+	return newTags
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
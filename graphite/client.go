@@ -2,12 +2,11 @@ package graphite
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
-	"io"
+	"math/rand"
 	"net"
-	"os"
-	"strings"
 	"time"
 )
 
@@ -23,6 +22,20 @@ const (
 	// DefaultMaxTries is the default maximum number of times to try sending
 	// metrics to the Graphite server.
 	DefaultMaxTries = 3
+
+	// DefaultReconnectMin is the default starting backoff between redial
+	// attempts. The first redial after a write failure is immediate; each
+	// later wait is jittered between half the current backoff and all of it.
+	DefaultReconnectMin = 100 * time.Millisecond
+
+	// DefaultReconnectMax is the default upper bound on the exponential
+	// backoff between redial attempts.
+	DefaultReconnectMax = 30 * time.Second
+
+	// defaultSpoolReplayInterval is how often Submit retries a non-empty
+	// disk spool while otherwise idle, so spooled overflow doesn't have to
+	// wait for the next reconnect to be replayed.
+	defaultSpoolReplayInterval = 30 * time.Second
 )
 
 var (
@@ -44,6 +57,11 @@ type Client interface {
 
 	// Submit submits the queued metrics to the Graphite server.
 	Submit(ctx context.Context) error
+
+	// DroppedMetrics returns the number of metrics dropped so far because
+	// they could neither be queued nor (if a disk spool is configured)
+	// spooled.
+	DroppedMetrics() uint64
 }
 
 type clientOptions struct {
@@ -64,6 +82,41 @@ type clientOptions struct {
 
 	// The address of the Graphite server. This is used if [Conn] is not set.
 	Addr string
+
+	// Protocol selects the wire encoding used to submit metrics.
+	Protocol Protocol
+
+	// encoder implements Protocol. Set from Protocol in setDefaults.
+	encoder Encoder
+
+	// Instance, Service and Version identify this client in its published
+	// expvar metrics and in any self-reported metrics.
+	Instance string
+	Service  string
+	Version  string
+
+	// SelfMetricsEnabled, SelfMetricsPrefix and SelfMetricsTags are set by
+	// WithSelfMetrics.
+	SelfMetricsEnabled bool
+	SelfMetricsPrefix  []string
+	SelfMetricsTags    map[string]string
+
+	// TLSEnabled and TLSConfig are set by WithTLS, and control how the
+	// client re-dials Addr when reconnecting.
+	TLSEnabled bool
+	TLSConfig  *tls.Config
+
+	// ReconnectMin and ReconnectMax bound the exponential backoff used
+	// between redial attempts. Set by WithReconnect.
+	ReconnectMin time.Duration
+	ReconnectMax time.Duration
+
+	// DropPolicy is set by WithDropPolicy.
+	DropPolicy DropPolicy
+
+	// SpoolDir and SpoolMaxBytes are set by WithDiskSpool.
+	SpoolDir      string
+	SpoolMaxBytes int64
 }
 
 // ClientOption represents an option that can be set on the Graphite client.
@@ -109,6 +162,65 @@ func WithAddress(addr string) ClientOption {
 	}
 }
 
+// WithInstance sets the instance identity published in this client's expvar
+// metrics and attached to its self-reported metrics.
+func WithInstance(instance string) ClientOption {
+	return func(c *clientOptions) {
+		c.Instance = instance
+	}
+}
+
+// WithService sets the service identity published in this client's expvar
+// metrics and attached to its self-reported metrics.
+func WithService(service string) ClientOption {
+	return func(c *clientOptions) {
+		c.Service = service
+	}
+}
+
+// WithVersion sets the version string published in this client's expvar
+// metrics.
+func WithVersion(version string) ClientOption {
+	return func(c *clientOptions) {
+		c.Version = version
+	}
+}
+
+// WithSelfMetrics enables periodically pushing this client's own expvar
+// health metrics (queued/dropped counts, submit byte/error/retry counters,
+// reconnects) back through itself to Graphite, under prefix and with tags
+// attached, so operators can graph the client alongside the metrics it
+// ships. The submit duration histogram is published via expvar only and is
+// not pushed.
+func WithSelfMetrics(prefix []string, tags map[string]string) ClientOption {
+	return func(c *clientOptions) {
+		c.SelfMetricsEnabled = true
+		c.SelfMetricsPrefix = prefix
+		c.SelfMetricsTags = tags
+	}
+}
+
+// WithTLS enables dialing the Graphite server over TLS whenever the client
+// needs to (re)connect using Addr. A nil config uses the standard library's
+// defaults.
+func WithTLS(config *tls.Config) ClientOption {
+	return func(c *clientOptions) {
+		c.TLSEnabled = true
+		c.TLSConfig = config
+	}
+}
+
+// WithReconnect sets the starting and maximum backoff used between redial
+// attempts after a write to the Graphite server fails. The first redial is
+// immediate; the backoff then doubles from min up to max, and each wait is
+// jittered between half the current backoff and all of it.
+func WithReconnect(min, max time.Duration) ClientOption {
+	return func(c *clientOptions) {
+		c.ReconnectMin = min
+		c.ReconnectMax = max
+	}
+}
+
 func (c *clientOptions) setDefaults(ctx context.Context) error {
 	if c.MaxBufferSize == 0 {
 		c.MaxBufferSize = DefaultMaxBufferSize
@@ -122,10 +234,22 @@ func (c *clientOptions) setDefaults(ctx context.Context) error {
 		c.MaxTries = DefaultMaxTries
 	}
 
+	if c.ReconnectMin == 0 {
+		c.ReconnectMin = DefaultReconnectMin
+	}
+
+	if c.ReconnectMax == 0 {
+		c.ReconnectMax = DefaultReconnectMax
+	}
+
 	if c.Conn == nil && c.Addr == "" {
 		return ErrNoAddress
 	}
 
+	if c.encoder == nil {
+		c.encoder = c.Protocol.encoder()
+	}
+
 	return nil
 }
 
@@ -133,6 +257,8 @@ type client struct {
 	clientOptions
 
 	queuedMetrics chan Metric
+	metrics       *clientMetrics
+	spool         *diskSpool
 }
 
 var _ Client = &client{}
@@ -149,24 +275,123 @@ func NewClient(ctx context.Context, opts ...ClientOption) (Client, error) {
 		return nil, err
 	}
 
-	return &client{
+	c := &client{
 		clientOptions: clientOptions,
 		queuedMetrics: make(chan Metric, clientOptions.MaxBufferSize),
-	}, nil
+		metrics: newClientMetrics(
+			clientOptions.Instance,
+			clientOptions.Service,
+			clientOptions.Version,
+		),
+	}
+
+	// An Addr-only client (the construction WithReconnect exists for) has
+	// no connection yet: dial it now, rather than leaving c.Conn nil until
+	// the first failed write tries to reconnect.
+	if c.Conn == nil {
+		if err := c.reconnect(ctx); err != nil {
+			return nil, fmt.Errorf("graphite: failed to dial %q: %w", clientOptions.Addr, err)
+		}
+	}
+
+	if clientOptions.SpoolDir != "" {
+		spool, err := newDiskSpool(clientOptions.SpoolDir, clientOptions.SpoolMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		c.spool = spool
+	}
+
+	if clientOptions.SelfMetricsEnabled {
+		go c.runSelfMetrics(ctx)
+	}
+
+	return c, nil
+}
+
+// dial opens a new connection to Addr, using TLS if WithTLS was given.
+func (c *client) dial() (net.Conn, error) {
+	if c.Addr == "" {
+		return nil, ErrNoAddress
+	}
+
+	if c.TLSEnabled {
+		return tls.Dial("tcp", c.Addr, c.TLSConfig)
+	}
+
+	return net.Dial("tcp", c.Addr)
+}
+
+// reconnect closes the current connection, if any, and re-dials Addr,
+// retrying with exponential backoff and jitter between ReconnectMin and
+// ReconnectMax until it succeeds or ctx is cancelled.
+func (c *client) reconnect(ctx context.Context) error {
+	if c.Conn != nil {
+		c.Conn.Close()
+	}
+
+	backoff := c.ReconnectMin
+
+	for {
+		conn, err := c.dial()
+		if err == nil {
+			c.Conn = conn
+			c.metrics.connectionReconnectsTotal.Add(1)
+
+			return nil
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff)+1))/2
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"graphite: context cancelled while reconnecting: %w",
+				ctx.Err(),
+			)
+		}
+
+		backoff *= 2
+		if backoff > c.ReconnectMax {
+			backoff = c.ReconnectMax
+		}
+	}
 }
 
 // Submit submits the queued metrics to the Graphite server.
 func (c *client) Submit(ctx context.Context) error {
-	go func() {
-		io.Copy(os.Stderr, c.Conn)
-	}()
+	// spoolTickerC periodically wakes the loop below to drain any metrics
+	// that spoolOrDrop persisted since the last replay, e.g. under
+	// DropOldest/DropNewest overflow with no reconnect ever happening. It
+	// stays nil (and so never fires) when no disk spool is configured.
+	var spoolTickerC <-chan time.Time
+
+	if c.spool != nil {
+		if err := c.replaySpool(ctx); err != nil {
+			return fmt.Errorf("graphite: failed to replay disk spool: %w", err)
+		}
+
+		spoolTicker := time.NewTicker(defaultSpoolReplayInterval)
+		defer spoolTicker.Stop()
+
+		spoolTickerC = spoolTicker.C
+	}
 
 	for {
-		metricStrings := make([]string, 0, c.MaxMetricsPerMessage)
+		metrics := make([]Metric, 0, c.MaxMetricsPerMessage)
 
 		select {
 		case metric := <-c.queuedMetrics:
-			metricStrings = append(metricStrings, metric.String())
+			metrics = append(metrics, metric)
+			c.metrics.queuedMetrics.Add(-1)
+		case <-spoolTickerC:
+			if err := c.replaySpool(ctx); err != nil {
+				return fmt.Errorf("graphite: failed to replay disk spool: %w", err)
+			}
+
+			continue
 		case <-ctx.Done():
 			return fmt.Errorf(
 				"graphite: context cancelled while submitting: %w",
@@ -179,7 +404,8 @@ func (c *client) Submit(ctx context.Context) error {
 		for i := 1; furtherMetrics && i < c.MaxMetricsPerMessage; i++ {
 			select {
 			case metric := <-c.queuedMetrics:
-				metricStrings = append(metricStrings, metric.String())
+				metrics = append(metrics, metric)
+				c.metrics.queuedMetrics.Add(-1)
 			case <-ctx.Done():
 				return fmt.Errorf(
 					"graphite: context cancelled while submitting: %w",
@@ -190,28 +416,63 @@ func (c *client) Submit(ctx context.Context) error {
 			}
 		}
 
-		if err := c.SubmitMetricsString(
-			ctx,
-			strings.Join(metricStrings, "\n"),
-		); err != nil {
+		if err := c.SubmitBatch(ctx, metrics); err != nil {
 			return fmt.Errorf("graphite: failed to submit metrics: %w", err)
 		}
 	}
 }
 
-// SubmitMetricsString submits the given metrics string to the Graphite server, retrying for [c.MaxTries] times.
-func (c *client) SubmitMetricsString(ctx context.Context, str string) (err error) {
-	// Ensure line termination.
-	if !strings.HasSuffix(str, "\n") {
-		str += "\n"
+// SubmitBatch encodes metrics using the client's configured Protocol and
+// submits them to the Graphite server, retrying for [c.MaxTries] times.
+func (c *client) SubmitBatch(ctx context.Context, metrics []Metric) error {
+	start := time.Now()
+	defer func() { c.metrics.submitDuration.Observe(time.Since(start)) }()
+
+	data, err := c.encoder.EncodeBatch(metrics)
+	if err != nil {
+		return fmt.Errorf("graphite: failed to encode metrics: %w", err)
 	}
 
 	for i := 0; i < c.MaxTries; i++ {
-		if _, err = c.Conn.Write([]byte(str)); err == nil {
+		if c.Conn == nil {
+			err = fmt.Errorf("graphite: no connection established")
+		} else if _, err = c.Conn.Write(data); err == nil {
+			c.metrics.submitBytesTotal.Add(int64(len(data)))
 			return nil
 		}
+
+		c.metrics.submitRetriesTotal.Add(1)
+
+		// Without a known Addr there is nothing to redial, so fall back to
+		// the old behaviour of simply trying the existing connection again.
+		if c.Addr == "" {
+			continue
+		}
+
+		if reconnectErr := c.reconnect(ctx); reconnectErr != nil {
+			c.metrics.submitErrorsTotal.Add(1)
+
+			return fmt.Errorf(
+				"graphite: failed to reconnect while submitting metrics: %w",
+				reconnectErr,
+			)
+		}
+
+		// The connection just recovered: get anything spooled while it was
+		// down back out ahead of the batch we're retrying, rather than
+		// waiting for the next spoolTickerC tick.
+		if c.spool != nil {
+			if err := c.replaySpool(ctx); err != nil {
+				return fmt.Errorf(
+					"graphite: failed to replay disk spool after reconnecting: %w",
+					err,
+				)
+			}
+		}
 	}
 
+	c.metrics.submitErrorsTotal.Add(1)
+
 	return fmt.Errorf(
 		"graphite: failed to send metrics after %d tries: %w",
 		c.MaxTries,
@@ -219,6 +480,65 @@ func (c *client) SubmitMetricsString(ctx context.Context, str string) (err error
 	)
 }
 
+// replaySpool drains any metrics persisted by a disk spool and submits them
+// ahead of newly queued metrics, preserving FIFO order. A segment is only
+// removed by diskSpool.Replay once its metrics are successfully submitted,
+// so metrics survive a failure partway through the replay.
+func (c *client) replaySpool(ctx context.Context) error {
+	return c.spool.Replay(func(metrics []Metric) error {
+		for start := 0; start < len(metrics); start += c.MaxMetricsPerMessage {
+			end := start + c.MaxMetricsPerMessage
+			if end > len(metrics) {
+				end = len(metrics)
+			}
+
+			if err := c.SubmitBatch(ctx, metrics[start:end]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// runSelfMetrics periodically pushes the client's own expvar health metrics
+// back through itself until ctx is cancelled.
+func (c *client) runSelfMetrics(ctx context.Context) {
+	ticker := time.NewTicker(defaultSelfMetricsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.pushSelfMetrics(ctx)
+		}
+	}
+}
+
+func (c *client) pushSelfMetrics(ctx context.Context) {
+	metadata := MetricMetadata{Name: c.SelfMetricsPrefix, Tags: c.SelfMetricsTags}
+	timestamp := time.Now()
+
+	values := map[string]string{
+		"queued_metrics":              c.metrics.queuedMetrics.String(),
+		"dropped_metrics":             c.metrics.droppedMetrics.String(),
+		"submit_bytes_total":          c.metrics.submitBytesTotal.String(),
+		"submit_errors_total":         c.metrics.submitErrorsTotal.String(),
+		"submit_retries_total":        c.metrics.submitRetriesTotal.String(),
+		"connection_reconnects_total": c.metrics.connectionReconnectsTotal.String(),
+	}
+
+	for name, value := range values {
+		metric := metadata.SubMetric(name, metadata.Tags)
+
+		// Self metrics are best-effort: a failed push here shouldn't take
+		// down the caller's own Submit loop.
+		_ = c.SendMetric(ctx, *metric, value, timestamp)
+	}
+}
+
 // SendMetric sends a metric to the configured metric server.
 func (c *client) SendMetric(
 	ctx context.Context,
@@ -232,10 +552,64 @@ func (c *client) SendMetric(
 		Timestamp:      timestamp,
 	}
 
-	select {
-	case c.queuedMetrics <- queuedMetric:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
+	switch c.DropPolicy {
+	case DropOldest:
+		select {
+		case c.queuedMetrics <- queuedMetric:
+			c.metrics.queuedMetrics.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		select {
+		case oldest := <-c.queuedMetrics:
+			c.metrics.queuedMetrics.Add(-1)
+			c.spoolOrDrop(oldest)
+		default:
+		}
+
+		select {
+		case c.queuedMetrics <- queuedMetric:
+			c.metrics.queuedMetrics.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case DropNewest:
+		select {
+		case c.queuedMetrics <- queuedMetric:
+			c.metrics.queuedMetrics.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			c.spoolOrDrop(queuedMetric)
+			return nil
+		}
+	default: // DropBlock
+		select {
+		case c.queuedMetrics <- queuedMetric:
+			c.metrics.queuedMetrics.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 }
+
+// spoolOrDrop persists metric to the disk spool if one is configured,
+// falling back to incrementing droppedMetrics if spooling isn't possible.
+func (c *client) spoolOrDrop(metric Metric) {
+	if c.spool != nil && c.spool.Write(metric) == nil {
+		return
+	}
+
+	c.metrics.droppedMetrics.Add(1)
+}
+
+// DroppedMetrics implements Client.
+func (c *client) DroppedMetrics() uint64 {
+	return uint64(c.metrics.droppedMetrics.Value())
+}
@@ -0,0 +1,284 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendMetricDropNewest(t *testing.T) {
+	ctx := context.Background()
+
+	_, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	clientIface, err := NewClient(ctx,
+		WithConnection(clientConn),
+		WithMaxBufferSize(1),
+		WithDropPolicy(DropNewest),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+
+	c := clientIface.(*client)
+
+	metadata := MetricMetadata{Name: []string{"test", "metric"}}
+
+	if err := c.SendMetric(ctx, metadata, "1", time.Now()); err != nil {
+		t.Fatalf("first SendMetric returned an error: %v", err)
+	}
+
+	if err := c.SendMetric(ctx, metadata, "2", time.Now()); err != nil {
+		t.Fatalf("second SendMetric returned an error: %v", err)
+	}
+
+	if got := len(c.queuedMetrics); got != 1 {
+		t.Fatalf("expected 1 queued metric, got %d", got)
+	}
+
+	queued := <-c.queuedMetrics
+	if queued.Value != "1" {
+		t.Errorf("expected the first metric to survive DropNewest, got value %q", queued.Value)
+	}
+
+	if got := c.metrics.droppedMetrics.Value(); got != 1 {
+		t.Errorf("expected droppedMetrics to be 1, got %d", got)
+	}
+}
+
+func TestSendMetricDropOldest(t *testing.T) {
+	ctx := context.Background()
+
+	_, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	clientIface, err := NewClient(ctx,
+		WithConnection(clientConn),
+		WithMaxBufferSize(1),
+		WithDropPolicy(DropOldest),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+
+	c := clientIface.(*client)
+
+	metadata := MetricMetadata{Name: []string{"test", "metric"}}
+
+	if err := c.SendMetric(ctx, metadata, "1", time.Now()); err != nil {
+		t.Fatalf("first SendMetric returned an error: %v", err)
+	}
+
+	if err := c.SendMetric(ctx, metadata, "2", time.Now()); err != nil {
+		t.Fatalf("second SendMetric returned an error: %v", err)
+	}
+
+	if got := len(c.queuedMetrics); got != 1 {
+		t.Fatalf("expected 1 queued metric, got %d", got)
+	}
+
+	queued := <-c.queuedMetrics
+	if queued.Value != "2" {
+		t.Errorf("expected the newest metric to survive DropOldest, got value %q", queued.Value)
+	}
+
+	if got := c.metrics.droppedMetrics.Value(); got != 1 {
+		t.Errorf("expected droppedMetrics to be 1, got %d", got)
+	}
+}
+
+// midBatchFailConn closes the underlying connection after its first Write,
+// simulating a server that drops the connection mid-batch.
+type midBatchFailConn struct {
+	net.Conn
+
+	mu      sync.Mutex
+	written bool
+}
+
+func (c *midBatchFailConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.written {
+		return 0, fmt.Errorf("midBatchFailConn: write after close")
+	}
+
+	c.written = true
+	c.Conn.Close()
+
+	return 0, fmt.Errorf("simulated mid-batch connection failure")
+}
+
+// faultInjectingListener wraps a net.Listener so that the first
+// failures accepted connections immediately fail their first write,
+// as if the server had closed the connection mid-batch.
+type faultInjectingListener struct {
+	net.Listener
+
+	failures int
+
+	mu      sync.Mutex
+	accepts int
+}
+
+func (l *faultInjectingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.accepts++
+	faulty := l.accepts <= l.failures
+	l.mu.Unlock()
+
+	if faulty {
+		return &midBatchFailConn{Conn: conn}, nil
+	}
+
+	return conn, nil
+}
+
+// TestSubmitReconnectsAfterMidBatchFailure exercises the reconnect path end
+// to end: the client's initial connection (one end of a net.Pipe wrapped in
+// midBatchFailConn) fails its first write, and SubmitBatch must redial Addr
+// and deliver the metric on the new connection rather than losing it.
+func TestSubmitReconnectsAfterMidBatchFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	faultyLn := &faultInjectingListener{Listener: ln, failures: 1}
+
+	received := make(chan string, 1)
+
+	go func() {
+		for {
+			conn, err := faultyLn.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				buf := make([]byte, 4096)
+
+				n, err := conn.Read(buf)
+				if err != nil {
+					return
+				}
+
+				received <- string(buf[:n])
+			}(conn)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, pipeConn := net.Pipe()
+	defer pipeConn.Close()
+
+	clientIface, err := NewClient(ctx,
+		WithConnection(&midBatchFailConn{Conn: pipeConn}),
+		WithAddress(ln.Addr().String()),
+		WithReconnect(time.Millisecond, 10*time.Millisecond),
+		WithMaxTries(5),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+
+	if err := clientIface.SendMetric(ctx, MetricMetadata{Name: []string{"test"}}, "42", time.Now()); err != nil {
+		t.Fatalf("SendMetric returned an error: %v", err)
+	}
+
+	go clientIface.Submit(ctx)
+
+	select {
+	case line := <-received:
+		if !strings.Contains(line, "test 42") {
+			t.Errorf("expected the reconnected write to contain the metric, got %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the client to reconnect and deliver the metric")
+	}
+}
+
+// TestReplaySpoolAfterReconnect checks that a metric spooled to disk (as if
+// by a prior overflow) is drained by replaySpool on demand, rather than
+// only once at Submit's entry — replaySpool is what SubmitBatch calls
+// immediately after a successful reconnect.
+func TestReplaySpoolAfterReconnect(t *testing.T) {
+	spoolDir := t.TempDir()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		buf := make([]byte, 4096)
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+
+		received <- string(buf[:n])
+	}()
+
+	ctx := context.Background()
+
+	clientIface, err := NewClient(ctx,
+		WithAddress(ln.Addr().String()),
+		WithDiskSpool(spoolDir, 1<<20),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned an error: %v", err)
+	}
+
+	c := clientIface.(*client)
+
+	// Simulate a metric that overflowed to the spool while the connection
+	// was down.
+	if err := c.spool.Write(Metric{
+		MetricMetadata: MetricMetadata{Name: []string{"spooled"}},
+		Value:          "1",
+		Timestamp:      time.Now(),
+	}); err != nil {
+		t.Fatalf("spool.Write returned an error: %v", err)
+	}
+
+	if err := c.replaySpool(ctx); err != nil {
+		t.Fatalf("replaySpool returned an error: %v", err)
+	}
+
+	select {
+	case line := <-received:
+		if !strings.Contains(line, "spooled 1") {
+			t.Errorf("expected the replayed spool entry, got %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the spooled metric to be replayed")
+	}
+}
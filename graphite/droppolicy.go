@@ -0,0 +1,41 @@
+package graphite
+
+// DropPolicy selects what SendMetric does when the in-memory queue is full.
+type DropPolicy int
+
+const (
+	// DropBlock blocks SendMetric until space is available or its context
+	// is cancelled. This is the default, and matches the client's
+	// original behaviour.
+	DropBlock DropPolicy = iota
+
+	// DropOldest evicts the oldest queued metric to make room for the new
+	// one. The evicted metric is persisted to the disk spool if one is
+	// configured, otherwise it is dropped and counted.
+	DropOldest
+
+	// DropNewest discards the metric currently being sent, leaving the
+	// queue untouched. The discarded metric is persisted to the disk spool
+	// if one is configured, otherwise it is dropped and counted.
+	DropNewest
+)
+
+// WithDropPolicy sets what SendMetric does when the in-memory queue fills
+// up. Defaults to DropBlock.
+func WithDropPolicy(policy DropPolicy) ClientOption {
+	return func(c *clientOptions) {
+		c.DropPolicy = policy
+	}
+}
+
+// WithDiskSpool enables overflow buffering to append-only segment files in
+// dir, bounded to maxBytes total across all segments. Metrics evicted or
+// discarded under DropOldest/DropNewest are spooled here instead of being
+// counted as dropped, and are replayed FIFO, ahead of newly queued metrics,
+// the next time Submit runs.
+func WithDiskSpool(dir string, maxBytes int64) ClientOption {
+	return func(c *clientOptions) {
+		c.SpoolDir = dir
+		c.SpoolMaxBytes = maxBytes
+	}
+}
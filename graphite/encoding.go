@@ -0,0 +1,63 @@
+package graphite
+
+import "bytes"
+
+// Encoder encodes a batch of metrics into a single message ready to write
+// to the wire, in whatever format a particular Graphite/Carbon receiver
+// expects.
+type Encoder interface {
+	// EncodeBatch encodes up to a full batch of metrics into one message.
+	EncodeBatch(metrics []Metric) ([]byte, error)
+}
+
+// Protocol selects the wire encoding used when submitting metrics.
+type Protocol int
+
+const (
+	// ProtoPlaintext is Graphite's plaintext line protocol: one
+	// "path value timestamp" line per metric. This is the default.
+	ProtoPlaintext Protocol = iota
+
+	// ProtoPickle is Carbon's pickle protocol, as consumed by Carbon's
+	// MetricPickleReceiver.
+	ProtoPickle
+
+	// ProtoMsgpack is a MessagePack encoding, as consumed by relays such as
+	// carbon-c-relay's msgpack input.
+	ProtoMsgpack
+)
+
+func (p Protocol) encoder() Encoder {
+	switch p {
+	case ProtoPickle:
+		return PickleEncoder{}
+	case ProtoMsgpack:
+		return MsgpackEncoder{}
+	default:
+		return PlaintextEncoder{}
+	}
+}
+
+// WithProtocol selects the wire protocol used to submit metrics. Defaults
+// to ProtoPlaintext.
+func WithProtocol(protocol Protocol) ClientOption {
+	return func(c *clientOptions) {
+		c.Protocol = protocol
+	}
+}
+
+// PlaintextEncoder encodes metrics using Graphite's plaintext line
+// protocol.
+type PlaintextEncoder struct{}
+
+// EncodeBatch implements Encoder.
+func (PlaintextEncoder) EncodeBatch(metrics []Metric) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, metric := range metrics {
+		buf.WriteString(metric.String())
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
@@ -22,6 +22,28 @@ func (m MetricMetadata) SubMetric(name string, tags map[string]string) *MetricMe
 	}
 }
 
+// Path returns the metric's Graphite path: its dot-joined name, with any
+// tags appended in ";tag=value" form.
+func (m MetricMetadata) Path() string {
+	var path strings.Builder
+
+	path.WriteString(m.Name[0])
+
+	for _, n := range m.Name[1:] {
+		path.WriteRune('.')
+		path.WriteString(n)
+	}
+
+	for tag, value := range m.Tags {
+		path.WriteRune(';')
+		path.WriteString(tag)
+		path.WriteRune('=')
+		path.WriteString(value)
+	}
+
+	return path.String()
+}
+
 // Metric represents a metric that has been queued for sending to the
 // Graphite server.
 type Metric struct {
@@ -34,19 +56,7 @@ type Metric struct {
 func (m Metric) String() string {
 	var metricString strings.Builder
 
-	metricString.WriteString(m.Name[0])
-
-	for _, n := range m.Name[1:] {
-		metricString.WriteRune('.')
-		metricString.WriteString(n)
-	}
-
-	for tag, value := range m.Tags {
-		metricString.WriteRune(';')
-		metricString.WriteString(tag)
-		metricString.WriteRune('=')
-		metricString.WriteString(value)
-	}
+	metricString.WriteString(m.Path())
 
 	metricString.WriteRune(' ')
 	metricString.WriteString(m.Value)
@@ -0,0 +1,130 @@
+package graphite
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// clientSeq disambiguates the expvar namespace of multiple Clients created
+// in the same process, since expvar.Publish panics on a duplicate name.
+var clientSeq int64
+
+// defaultSelfMetricsInterval is how often a client configured with
+// WithSelfMetrics pushes its own health metrics to Graphite.
+const defaultSelfMetricsInterval = 60 * time.Second
+
+// clientMetrics holds the expvar variables published for one Client's own
+// health.
+type clientMetrics struct {
+	queuedMetrics             *expvar.Int
+	droppedMetrics            *expvar.Int
+	submitBytesTotal          *expvar.Int
+	submitErrorsTotal         *expvar.Int
+	submitRetriesTotal        *expvar.Int
+	submitDuration            *durationHistogram
+	connectionReconnectsTotal *expvar.Int
+}
+
+func newClientMetrics(instance, service, version string) *clientMetrics {
+	namespace := fmt.Sprintf("graphite_client_%d_", atomic.AddInt64(&clientSeq, 1))
+
+	metrics := &clientMetrics{
+		queuedMetrics:             expvar.NewInt(namespace + "queued_metrics"),
+		droppedMetrics:            expvar.NewInt(namespace + "dropped_metrics"),
+		submitBytesTotal:          expvar.NewInt(namespace + "submit_bytes_total"),
+		submitErrorsTotal:         expvar.NewInt(namespace + "submit_errors_total"),
+		submitRetriesTotal:        expvar.NewInt(namespace + "submit_retries_total"),
+		submitDuration:            newDurationHistogram(namespace + "submit_duration_seconds"),
+		connectionReconnectsTotal: expvar.NewInt(namespace + "connection_reconnects_total"),
+	}
+
+	expvar.NewString(namespace + "instance").Set(instance)
+	expvar.NewString(namespace + "service").Set(service)
+	expvar.NewString(namespace + "version").Set(version)
+	expvar.NewString(namespace + "start_time").Set(time.Now().UTC().Format(time.RFC3339))
+
+	return metrics
+}
+
+// defaultHistogramBuckets are the upper bounds, in ascending order, of the
+// submit_duration_seconds histogram's buckets.
+var defaultHistogramBuckets = []time.Duration{
+	1 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	5 * time.Second,
+}
+
+// durationHistogram is a small fixed-bucket histogram satisfying
+// expvar.Var, used for submit_duration_seconds.
+type durationHistogram struct {
+	buckets []time.Duration
+	counts  []int64
+	sum     int64 // nanoseconds
+	count   int64
+}
+
+func newDurationHistogram(name string) *durationHistogram {
+	histogram := &durationHistogram{
+		buckets: defaultHistogramBuckets,
+		counts:  make([]int64, len(defaultHistogramBuckets)+1),
+	}
+
+	expvar.Publish(name, histogram)
+
+	return histogram
+}
+
+// Observe records a single duration sample.
+func (h *durationHistogram) Observe(d time.Duration) {
+	atomic.AddInt64(&h.sum, int64(d))
+	atomic.AddInt64(&h.count, 1)
+
+	for i, bucket := range h.buckets {
+		if d <= bucket {
+			atomic.AddInt64(&h.counts[i], 1)
+			return
+		}
+	}
+
+	atomic.AddInt64(&h.counts[len(h.counts)-1], 1)
+}
+
+// String implements expvar.Var.
+func (h *durationHistogram) String() string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, `{"count":%d,"sum_seconds":%f,"buckets":{`,
+		atomic.LoadInt64(&h.count),
+		time.Duration(atomic.LoadInt64(&h.sum)).Seconds(),
+	)
+
+	for i, bucket := range h.buckets {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		fmt.Fprintf(&buf, `"%s":%d`, bucket, atomic.LoadInt64(&h.counts[i]))
+	}
+
+	fmt.Fprintf(&buf, `,"+Inf":%d}}`, atomic.LoadInt64(&h.counts[len(h.counts)-1]))
+
+	return buf.String()
+}
+
+// DebugVarsHandler returns the standard expvar HTTP handler, serving every
+// variable published in the process (including each Client's own metrics)
+// as JSON. Mount it wherever convenient, e.g.:
+//
+//	mux.Handle("/debug/vars", graphite.DebugVarsHandler())
+func DebugVarsHandler() http.Handler {
+	return expvar.Handler()
+}
@@ -0,0 +1,77 @@
+package graphite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// MsgpackEncoder encodes each metric as a MessagePack 3-element array of
+// [path, timestamp, value], as consumed by relays such as carbon-c-relay's
+// msgpack input. MessagePack values are self-delimiting, so a batch is
+// simply the concatenation of each metric's encoded array with no
+// additional framing.
+type MsgpackEncoder struct{}
+
+// EncodeBatch implements Encoder.
+func (MsgpackEncoder) EncodeBatch(metrics []Metric) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, metric := range metrics {
+		value, err := strconv.ParseFloat(metric.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("graphite: msgpack encoder: invalid metric value %q: %w", metric.Value, err)
+		}
+
+		writeMsgpackArrayHeader(&buf, 3)
+		writeMsgpackString(&buf, metric.Path())
+		writeMsgpackInt64(&buf, metric.Timestamp.Unix())
+		writeMsgpackFloat64(&buf, value)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeMsgpackArrayHeader(buf *bytes.Buffer, length int) {
+	switch {
+	case length <= 0x0f:
+		buf.WriteByte(0x90 | byte(length))
+	case length <= 0xffff:
+		buf.WriteByte(0xdc)
+		_ = binary.Write(buf, binary.BigEndian, uint16(length))
+	default:
+		buf.WriteByte(0xdd)
+		_ = binary.Write(buf, binary.BigEndian, uint32(length))
+	}
+}
+
+func writeMsgpackString(buf *bytes.Buffer, s string) {
+	data := []byte(s)
+
+	switch {
+	case len(data) <= 0x1f:
+		buf.WriteByte(0xa0 | byte(len(data)))
+	case len(data) <= 0xff:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(len(data)))
+	case len(data) <= 0xffff:
+		buf.WriteByte(0xda)
+		_ = binary.Write(buf, binary.BigEndian, uint16(len(data)))
+	default:
+		buf.WriteByte(0xdb)
+		_ = binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	}
+
+	buf.Write(data)
+}
+
+func writeMsgpackInt64(buf *bytes.Buffer, value int64) {
+	buf.WriteByte(0xd3)
+	_ = binary.Write(buf, binary.BigEndian, value)
+}
+
+func writeMsgpackFloat64(buf *bytes.Buffer, value float64) {
+	buf.WriteByte(0xcb)
+	_ = binary.Write(buf, binary.BigEndian, value)
+}
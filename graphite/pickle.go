@@ -0,0 +1,90 @@
+package graphite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Pickle protocol 2 opcodes, as used to build a list of
+// (path, (timestamp, value)) tuples compatible with Carbon's
+// MetricPickleReceiver.
+const (
+	pickleOpProto        = 0x80
+	pickleOpEmptyList    = ']'
+	pickleOpMark         = '('
+	pickleOpAppends      = 'e'
+	pickleOpStop         = '.'
+	pickleOpTuple2       = 0x86
+	pickleOpBinFloat     = 'G'
+	pickleOpShortBinStr  = 'U'
+	pickleOpBinString    = 'T'
+	pickleProtocolNumber = 2
+)
+
+// PickleEncoder encodes metrics as a Python-pickle-framed batch compatible
+// with Carbon's MetricPickleReceiver: a list of (path, (timestamp, value))
+// tuples, pickled with protocol 2 and framed with a 4-byte big-endian
+// length prefix, matching what `pickle.dumps(data, protocol=2)` followed by
+// `struct.pack("!L", len(data))` produces on the Carbon side.
+type PickleEncoder struct{}
+
+// EncodeBatch implements Encoder.
+func (PickleEncoder) EncodeBatch(metrics []Metric) ([]byte, error) {
+	var body bytes.Buffer
+
+	body.WriteByte(pickleOpProto)
+	body.WriteByte(pickleProtocolNumber)
+	body.WriteByte(pickleOpEmptyList)
+	body.WriteByte(pickleOpMark)
+
+	for _, metric := range metrics {
+		value, err := strconv.ParseFloat(metric.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("graphite: pickle encoder: invalid metric value %q: %w", metric.Value, err)
+		}
+
+		writePickleString(&body, metric.Path())
+		writePickleFloat(&body, float64(metric.Timestamp.Unix()))
+		writePickleFloat(&body, value)
+		body.WriteByte(pickleOpTuple2) // (timestamp, value)
+		body.WriteByte(pickleOpTuple2) // (path, (timestamp, value))
+	}
+
+	body.WriteByte(pickleOpAppends)
+	body.WriteByte(pickleOpStop)
+
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(body.Len()))
+
+	return append(header, body.Bytes()...), nil
+}
+
+func writePickleString(buf *bytes.Buffer, s string) {
+	data := []byte(s)
+
+	if len(data) < 256 {
+		buf.WriteByte(pickleOpShortBinStr)
+		buf.WriteByte(byte(len(data)))
+	} else {
+		buf.WriteByte(pickleOpBinString)
+
+		length := make([]byte, 4)
+		binary.LittleEndian.PutUint32(length, uint32(len(data)))
+		buf.Write(length)
+	}
+
+	buf.Write(data)
+}
+
+// writePickleFloat writes the BINFLOAT opcode, which is the one pickle
+// opcode encoded big-endian rather than little-endian.
+func writePickleFloat(buf *bytes.Buffer, value float64) {
+	buf.WriteByte(pickleOpBinFloat)
+
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, math.Float64bits(value))
+	buf.Write(data)
+}
@@ -0,0 +1,239 @@
+package graphite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pickleTuple is what decodePickleBatch produces for one
+// (path, (timestamp, value)) entry.
+type pickleTuple struct {
+	path      string
+	timestamp int64
+	value     float64
+}
+
+// decodePickleBatch is a minimal protocol-2 pickle reader: just enough to
+// decode what PickleEncoder.EncodeBatch produces (a 4-byte big-endian
+// length-prefixed list of (path, (timestamp, value)) tuples), to validate
+// compatibility with Carbon's MetricPickleReceiver without depending on a
+// real Python pickle implementation.
+func decodePickleBatch(t *testing.T, data []byte) []pickleTuple {
+	t.Helper()
+
+	if len(data) < 4 {
+		t.Fatalf("pickle batch too short: %d bytes", len(data))
+	}
+
+	length := binary.BigEndian.Uint32(data[:4])
+	body := data[4:]
+
+	if uint32(len(body)) != length {
+		t.Fatalf("pickle length prefix %d doesn't match body length %d", length, len(body))
+	}
+
+	r := bytes.NewReader(body)
+
+	readByte := func() byte {
+		b, err := r.ReadByte()
+		if err != nil {
+			t.Fatalf("unexpected end of pickle stream: %v", err)
+		}
+
+		return b
+	}
+
+	if got := readByte(); got != pickleOpProto {
+		t.Fatalf("expected PROTO opcode, got %#x", got)
+	}
+
+	if got := readByte(); got != pickleProtocolNumber {
+		t.Fatalf("expected protocol 2, got %d", got)
+	}
+
+	if got := readByte(); got != pickleOpEmptyList {
+		t.Fatalf("expected EMPTY_LIST opcode, got %#x", got)
+	}
+
+	if got := readByte(); got != pickleOpMark {
+		t.Fatalf("expected MARK opcode, got %#x", got)
+	}
+
+	var tuples []pickleTuple
+
+	for {
+		op := readByte()
+
+		if op == pickleOpAppends {
+			break
+		}
+
+		path := readPickleString(t, r, op)
+		timestamp := readPickleFloat(t, r, readByte())
+		value := readPickleFloat(t, r, readByte())
+
+		if got := readByte(); got != pickleOpTuple2 {
+			t.Fatalf("expected TUPLE2 opcode for (timestamp, value), got %#x", got)
+		}
+
+		if got := readByte(); got != pickleOpTuple2 {
+			t.Fatalf("expected TUPLE2 opcode for (path, (timestamp, value)), got %#x", got)
+		}
+
+		tuples = append(tuples, pickleTuple{
+			path:      path,
+			timestamp: int64(timestamp),
+			value:     value,
+		})
+	}
+
+	if got := readByte(); got != pickleOpStop {
+		t.Fatalf("expected STOP opcode, got %#x", got)
+	}
+
+	if r.Len() != 0 {
+		t.Fatalf("%d trailing bytes after STOP", r.Len())
+	}
+
+	return tuples
+}
+
+func readPickleString(t *testing.T, r *bytes.Reader, op byte) string {
+	t.Helper()
+
+	switch op {
+	case pickleOpShortBinStr:
+		n, err := r.ReadByte()
+		if err != nil {
+			t.Fatalf("unexpected end of pickle stream reading SHORT_BINSTRING length: %v", err)
+		}
+
+		buf := make([]byte, n)
+		if _, err := r.Read(buf); err != nil {
+			t.Fatalf("unexpected end of pickle stream reading SHORT_BINSTRING: %v", err)
+		}
+
+		return string(buf)
+	case pickleOpBinString:
+		lengthBytes := make([]byte, 4)
+
+		if _, err := r.Read(lengthBytes); err != nil {
+			t.Fatalf("unexpected end of pickle stream reading BINSTRING length: %v", err)
+		}
+
+		n := binary.LittleEndian.Uint32(lengthBytes)
+		buf := make([]byte, n)
+
+		if _, err := r.Read(buf); err != nil {
+			t.Fatalf("unexpected end of pickle stream reading BINSTRING: %v", err)
+		}
+
+		return string(buf)
+	default:
+		t.Fatalf("expected SHORT_BINSTRING or BINSTRING opcode, got %#x", op)
+
+		return ""
+	}
+}
+
+func readPickleFloat(t *testing.T, r *bytes.Reader, op byte) float64 {
+	t.Helper()
+
+	if op != pickleOpBinFloat {
+		t.Fatalf("expected BINFLOAT opcode, got %#x", op)
+	}
+
+	buf := make([]byte, 8)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("unexpected end of pickle stream reading BINFLOAT: %v", err)
+	}
+
+	return math.Float64frombits(binary.BigEndian.Uint64(buf))
+}
+
+func TestPickleEncoderEncodeBatch(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+
+	metrics := []Metric{
+		{
+			MetricMetadata: MetricMetadata{Name: []string{"servers", "web1", "cpu"}},
+			Value:          "0.5",
+			Timestamp:      timestamp,
+		},
+		{
+			MetricMetadata: MetricMetadata{
+				Name: []string{"servers", "web1", "mem"},
+				Tags: map[string]string{"unit": "bytes"},
+			},
+			Value:     "123456",
+			Timestamp: timestamp.Add(time.Second),
+		},
+	}
+
+	data, err := PickleEncoder{}.EncodeBatch(metrics)
+	if err != nil {
+		t.Fatalf("EncodeBatch returned an error: %v", err)
+	}
+
+	tuples := decodePickleBatch(t, data)
+
+	if len(tuples) != len(metrics) {
+		t.Fatalf("expected %d tuples, got %d", len(metrics), len(tuples))
+	}
+
+	for i, metric := range metrics {
+		tuple := tuples[i]
+
+		if tuple.path != metric.Path() {
+			t.Errorf("tuple %d: expected path %q, got %q", i, metric.Path(), tuple.path)
+		}
+
+		if tuple.timestamp != metric.Timestamp.Unix() {
+			t.Errorf("tuple %d: expected timestamp %d, got %d", i, metric.Timestamp.Unix(), tuple.timestamp)
+		}
+
+		wantValue, err := strconv.ParseFloat(metric.Value, 64)
+		if err != nil {
+			t.Fatalf("test metric %d has non-numeric value %q: %v", i, metric.Value, err)
+		}
+
+		if tuple.value != wantValue {
+			t.Errorf("tuple %d: expected value %v, got %v", i, wantValue, tuple.value)
+		}
+	}
+}
+
+// TestPickleEncoderEncodeBatchLongPath exercises the BINSTRING path (used
+// for names 256 bytes or longer), rather than the common SHORT_BINSTRING
+// case covered above.
+func TestPickleEncoderEncodeBatchLongPath(t *testing.T) {
+	longSegment := strings.Repeat("x", 300)
+
+	metrics := []Metric{
+		{
+			MetricMetadata: MetricMetadata{Name: []string{"servers", longSegment}},
+			Value:          "1",
+			Timestamp:      time.Unix(1700000000, 0),
+		},
+	}
+
+	data, err := PickleEncoder{}.EncodeBatch(metrics)
+	if err != nil {
+		t.Fatalf("EncodeBatch returned an error: %v", err)
+	}
+
+	tuples := decodePickleBatch(t, data)
+
+	if len(tuples) != 1 {
+		t.Fatalf("expected 1 tuple, got %d", len(tuples))
+	}
+
+	if tuples[0].path != metrics[0].Path() {
+		t.Errorf("expected path %q, got %q", metrics[0].Path(), tuples[0].path)
+	}
+}
@@ -0,0 +1,241 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+// AggregationFunc reduces the values collected in an aggregation window to
+// a single output value.
+type AggregationFunc int
+
+const (
+	// AggregationSum reports the sum of values collected in the window.
+	AggregationSum AggregationFunc = iota
+
+	// AggregationAvg reports the mean of values collected in the window.
+	AggregationAvg
+
+	// AggregationMin reports the smallest value collected in the window.
+	AggregationMin
+
+	// AggregationMax reports the largest value collected in the window.
+	AggregationMax
+
+	// AggregationCount reports the number of values collected in the
+	// window.
+	AggregationCount
+)
+
+// sample is one value fed into an Aggregator bucket, kept alongside its
+// arrival time (to evict it once it ages out of Window) and the tags it
+// arrived with.
+type sample struct {
+	value     float64
+	tags      map[string]string
+	timestamp time.Time
+}
+
+// Aggregator buckets metrics whose dotted name matches Pattern, keyed by the
+// name produced by expanding OutputName with Pattern's capture groups (e.g.
+// "$1.avg"), and flushes a Func-reduced value per bucket to Destinations
+// every FlushInterval. The value reduced at each flush is computed over a
+// sliding window of the last Window worth of samples, not just those added
+// since the previous flush, so e.g. a 5-minute Window flushed every 10
+// seconds reports a continuously-updating trailing-5-minute aggregate
+// rather than a tumbling 10-second one.
+type Aggregator struct {
+	Pattern       *regexp.Regexp
+	OutputName    string
+	Func          AggregationFunc
+	Window        time.Duration
+	FlushInterval time.Duration
+	Destinations  []*Destination
+
+	mu      sync.Mutex
+	buckets map[string][]sample
+}
+
+// NewAggregator creates an Aggregator for the given pattern, output name
+// template and reduction function. Each flush, every window worth of
+// samples still in a bucket is reduced and sent to destinations; flushes
+// happen every flushInterval.
+func NewAggregator(
+	pattern *regexp.Regexp,
+	outputName string,
+	fn AggregationFunc,
+	window time.Duration,
+	flushInterval time.Duration,
+	destinations ...*Destination,
+) *Aggregator {
+	return &Aggregator{
+		Pattern:       pattern,
+		OutputName:    outputName,
+		Func:          fn,
+		Window:        window,
+		FlushInterval: flushInterval,
+		Destinations:  destinations,
+		buckets:       make(map[string][]sample),
+	}
+}
+
+// Add feeds metric into the aggregator if its name matches Pattern,
+// reporting whether it was consumed: a consumed metric should not also be
+// forwarded through a Route.
+func (a *Aggregator) Add(metric graphite.MetricMetadata, value string) bool {
+	name := strings.Join(metric.Name, ".")
+
+	matchIndices := a.Pattern.FindStringSubmatchIndex(name)
+	if matchIndices == nil {
+		return false
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+
+	key := string(a.Pattern.ExpandString(nil, a.OutputName, name, matchIndices))
+
+	a.mu.Lock()
+	a.buckets[key] = append(a.buckets[key], sample{
+		value:     floatValue,
+		tags:      metric.Tags,
+		timestamp: time.Now(),
+	})
+	a.mu.Unlock()
+
+	return true
+}
+
+// Run flushes the aggregator's buckets every FlushInterval until ctx is
+// cancelled.
+func (a *Aggregator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("router: context cancelled while running aggregator: %w", ctx.Err())
+		case <-ticker.C:
+			if err := a.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// flush reduces each bucket's samples still within the sliding Window and
+// sends the result to Destinations, evicting samples that have aged out
+// (and buckets left with none) so the window actually slides rather than
+// growing forever.
+func (a *Aggregator) flush(ctx context.Context) error {
+	cutoff := time.Now().Add(-a.Window)
+
+	type reduced struct {
+		values []float64
+		tags   map[string]string
+	}
+
+	a.mu.Lock()
+
+	toSend := make(map[string]reduced, len(a.buckets))
+
+	for key, samples := range a.buckets {
+		kept := samples[:0]
+
+		for _, s := range samples {
+			if s.timestamp.After(cutoff) {
+				kept = append(kept, s)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(a.buckets, key)
+			continue
+		}
+
+		a.buckets[key] = kept
+
+		values := make([]float64, len(kept))
+		for i, s := range kept {
+			values[i] = s.value
+		}
+
+		// Tags for a key are taken from its most recently arrived sample
+		// still in the window: a key can be fed by metrics with differing
+		// tag sets (e.g. after a rewrite collapses several source names
+		// into one output name), and there is no way to merge two tag sets
+		// that disagree on a key, so the newest sample wins explicitly
+		// rather than some arbitrary map-iteration order.
+		toSend[key] = reduced{values: values, tags: kept[len(kept)-1].tags}
+	}
+
+	a.mu.Unlock()
+
+	timestamp := time.Now()
+
+	for key, r := range toSend {
+		metadata := graphite.MetricMetadata{Name: strings.Split(key, "."), Tags: r.tags}
+		value := strconv.FormatFloat(reduce(a.Func, r.values), 'g', -1, 64)
+
+		for _, dest := range a.Destinations {
+			if err := dest.SendMetric(ctx, metadata, value, timestamp); err != nil {
+				return fmt.Errorf("router: failed to flush aggregate %q: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func reduce(fn AggregationFunc, values []float64) float64 {
+	switch fn {
+	case AggregationAvg:
+		return sum(values) / float64(len(values))
+	case AggregationMin:
+		min := values[0]
+
+		for _, value := range values[1:] {
+			if value < min {
+				min = value
+			}
+		}
+
+		return min
+	case AggregationMax:
+		max := values[0]
+
+		for _, value := range values[1:] {
+			if value > max {
+				max = value
+			}
+		}
+
+		return max
+	case AggregationCount:
+		return float64(len(values))
+	case AggregationSum:
+		return sum(values)
+	default:
+		return sum(values)
+	}
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+
+	for _, value := range values {
+		total += value
+	}
+
+	return total
+}
@@ -0,0 +1,129 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+// ErrNoAddress is returned when a Destination is configured without an
+// address to connect to.
+var ErrNoAddress = errors.New("router: no address specified for destination")
+
+// DestinationConfig configures a Destination.
+type DestinationConfig struct {
+	// Addr is the address of the Graphite/Carbon backend this destination
+	// writes to.
+	Addr string
+
+	// TLS enables a TLS connection to Addr.
+	TLS bool
+
+	// MaxBufferSize is the number of metrics this destination will queue in
+	// memory before SendMetric blocks (or, under DropPolicy, evicts or
+	// discards one). Defaults to graphite.DefaultMaxBufferSize.
+	MaxBufferSize int
+
+	// MaxTries is the number of write attempts per batch, redialling Addr
+	// after each failed one, before the batch is abandoned and Run returns
+	// an error. The abandoned batch is not spooled. Defaults to
+	// graphite.DefaultMaxTries.
+	MaxTries int
+
+	// DropPolicy selects what SendMetric does when the in-memory queue is
+	// full. Defaults to graphite.DropBlock.
+	DropPolicy graphite.DropPolicy
+
+	// SpoolDir, if set, is a directory metrics evicted or discarded under
+	// DropPolicy are persisted to, and replayed from once the connection
+	// recovers. See [graphite.WithDiskSpool].
+	SpoolDir      string
+	SpoolMaxBytes int64
+}
+
+// Destination is one endpoint a Route can dispatch metrics to. It wraps a
+// graphite.Client, so a Destination's reconnect backoff, queue overflow
+// handling and disk spooling all come from the same implementation the
+// single-connection client uses, rather than a second copy of that logic
+// that could drift from it.
+//
+// This is the fan-out relay's equivalent of the single client's Submit
+// loop: each Destination runs its own underlying client.Submit rather than
+// there being one global one.
+type Destination struct {
+	config DestinationConfig
+	client graphite.Client
+}
+
+// NewDestination creates a Destination, filling in defaults for any unset
+// DestinationConfig fields. It dials (or begins redialling) Addr before
+// returning, per [graphite.NewClient]; cancel ctx to bound how long that
+// initial connect is allowed to take.
+func NewDestination(ctx context.Context, config DestinationConfig) (*Destination, error) {
+	if config.Addr == "" {
+		return nil, ErrNoAddress
+	}
+
+	opts := []graphite.ClientOption{
+		graphite.WithAddress(config.Addr),
+		graphite.WithDropPolicy(config.DropPolicy),
+	}
+
+	if config.TLS {
+		opts = append(opts, graphite.WithTLS(nil))
+	}
+
+	if config.MaxBufferSize != 0 {
+		opts = append(opts, graphite.WithMaxBufferSize(config.MaxBufferSize))
+	}
+
+	if config.MaxTries != 0 {
+		opts = append(opts, graphite.WithMaxTries(config.MaxTries))
+	}
+
+	if config.SpoolDir != "" {
+		opts = append(opts, graphite.WithDiskSpool(config.SpoolDir, config.SpoolMaxBytes))
+	}
+
+	client, err := graphite.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("router: failed to create destination client for %q: %w", config.Addr, err)
+	}
+
+	return &Destination{config: config, client: client}, nil
+}
+
+// Addr returns the destination's configured address.
+func (d *Destination) Addr() string {
+	return d.config.Addr
+}
+
+// DroppedMetrics returns the number of metrics this destination has dropped
+// because they could neither be queued nor spooled.
+func (d *Destination) DroppedMetrics() uint64 {
+	return d.client.DroppedMetrics()
+}
+
+// SendMetric queues metric for delivery to this destination, per its
+// configured DropPolicy.
+func (d *Destination) SendMetric(
+	ctx context.Context,
+	metric graphite.MetricMetadata,
+	value string,
+	timestamp time.Time,
+) error {
+	return d.client.SendMetric(ctx, metric, value, timestamp)
+}
+
+// Run writes queued metrics to the destination's backend until ctx is
+// cancelled, via the underlying client's Submit loop.
+func (d *Destination) Run(ctx context.Context) error {
+	if err := d.client.Submit(ctx); err != nil {
+		return fmt.Errorf("router: destination %q: %w", d.config.Addr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package router
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+)
+
+// virtualNodesPerDestination is the number of points each destination
+// occupies on the hash ring, smoothing out load distribution.
+const virtualNodesPerDestination = 100
+
+type hashRingEntry struct {
+	hash uint32
+	dest *Destination
+}
+
+// hashRing implements consistent hashing over a fixed set of destinations,
+// so a given metric name is always routed to the same destination as long
+// as the destination set doesn't change.
+type hashRing struct {
+	entries []hashRingEntry
+}
+
+func newHashRing(destinations []*Destination) *hashRing {
+	ring := &hashRing{entries: make([]hashRingEntry, 0, len(destinations)*virtualNodesPerDestination)}
+
+	for _, dest := range destinations {
+		for i := 0; i < virtualNodesPerDestination; i++ {
+			key := fmt.Sprintf("%s-%d", dest.Addr(), i)
+			ring.entries = append(ring.entries, hashRingEntry{
+				hash: crc32.ChecksumIEEE([]byte(key)),
+				dest: dest,
+			})
+		}
+	}
+
+	sort.Slice(ring.entries, func(i, j int) bool {
+		return ring.entries[i].hash < ring.entries[j].hash
+	})
+
+	return ring
+}
+
+// Get returns the destination the given key hashes to.
+func (r *hashRing) Get(key string) *Destination {
+	if len(r.entries) == 0 {
+		return nil
+	}
+
+	hash := crc32.ChecksumIEEE([]byte(key))
+
+	idx := sort.Search(len(r.entries), func(i int) bool {
+		return r.entries[i].hash >= hash
+	})
+
+	if idx == len(r.entries) {
+		idx = 0
+	}
+
+	return r.entries[idx].dest
+}
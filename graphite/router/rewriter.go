@@ -0,0 +1,36 @@
+package router
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+// Rewriter rewrites a metric's dotted name by substituting regex matches,
+// mirroring carbon-relay-ng's rewrite rules.
+type Rewriter struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewRewriter creates a Rewriter that replaces matches of pattern in a
+// metric's dotted name with replacement, which may reference pattern's
+// capture groups (e.g. "$1").
+func NewRewriter(pattern *regexp.Regexp, replacement string) Rewriter {
+	return Rewriter{pattern: pattern, replacement: replacement}
+}
+
+// Rewrite returns metric with its name rewritten, if pattern matched.
+func (r Rewriter) Rewrite(metric graphite.MetricMetadata) graphite.MetricMetadata {
+	name := strings.Join(metric.Name, ".")
+
+	rewritten := r.pattern.ReplaceAllString(name, r.replacement)
+	if rewritten == name {
+		return metric
+	}
+
+	metric.Name = strings.Split(rewritten, ".")
+
+	return metric
+}
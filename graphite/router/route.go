@@ -0,0 +1,155 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+)
+
+// Route matches metrics by name and dispatches them to a set of
+// Destinations, modelled on carbon-relay-ng's routing table.
+type Route interface {
+	// Match reports whether this route handles the given dotted metric
+	// name.
+	Match(name string) bool
+
+	// Dispatch sends the metric to this route's destinations according to
+	// its dispatch policy. Metrics that don't match are silently ignored.
+	Dispatch(ctx context.Context,
+		metric graphite.MetricMetadata,
+		value string,
+		timestamp time.Time,
+	) error
+
+	// Destinations returns the route's configured destinations.
+	Destinations() []*Destination
+}
+
+type baseRoute struct {
+	pattern      *regexp.Regexp
+	destinations []*Destination
+}
+
+func (r *baseRoute) Match(name string) bool {
+	return r.pattern == nil || r.pattern.MatchString(name)
+}
+
+func (r *baseRoute) Destinations() []*Destination {
+	return r.destinations
+}
+
+// SendAllMatchRoute dispatches every matching metric to every one of its
+// destinations.
+type SendAllMatchRoute struct {
+	baseRoute
+}
+
+// NewSendAllMatchRoute creates a Route that sends every metric matching
+// pattern to all of destinations. A nil pattern matches every metric.
+func NewSendAllMatchRoute(pattern *regexp.Regexp, destinations ...*Destination) *SendAllMatchRoute {
+	return &SendAllMatchRoute{baseRoute{pattern: pattern, destinations: destinations}}
+}
+
+// Dispatch implements Route.
+func (r *SendAllMatchRoute) Dispatch(
+	ctx context.Context,
+	metric graphite.MetricMetadata,
+	value string,
+	timestamp time.Time,
+) error {
+	if !r.Match(strings.Join(metric.Name, ".")) {
+		return nil
+	}
+
+	for _, dest := range r.destinations {
+		if err := dest.SendMetric(ctx, metric, value, timestamp); err != nil {
+			return fmt.Errorf("router: failed to send to %q: %w", dest.Addr(), err)
+		}
+	}
+
+	return nil
+}
+
+// SendFirstMatchRoute dispatches a matching metric to the first of its
+// destinations that accepts it, trying the rest in order as failover.
+type SendFirstMatchRoute struct {
+	baseRoute
+}
+
+// NewSendFirstMatchRoute creates a Route that sends every metric matching
+// pattern to the first destination in destinations that accepts it. A nil
+// pattern matches every metric.
+func NewSendFirstMatchRoute(pattern *regexp.Regexp, destinations ...*Destination) *SendFirstMatchRoute {
+	return &SendFirstMatchRoute{baseRoute{pattern: pattern, destinations: destinations}}
+}
+
+// Dispatch implements Route.
+func (r *SendFirstMatchRoute) Dispatch(
+	ctx context.Context,
+	metric graphite.MetricMetadata,
+	value string,
+	timestamp time.Time,
+) error {
+	if !r.Match(strings.Join(metric.Name, ".")) {
+		return nil
+	}
+
+	var err error
+
+	for _, dest := range r.destinations {
+		if err = dest.SendMetric(ctx, metric, value, timestamp); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("router: all destinations failed: %w", err)
+}
+
+// ConsistentHashingRoute dispatches a matching metric to one destination
+// chosen by hashing its name, spreading metrics across destinations while
+// keeping any given metric on a stable destination as long as the
+// destination set doesn't change.
+type ConsistentHashingRoute struct {
+	baseRoute
+
+	ring *hashRing
+}
+
+// NewConsistentHashingRoute creates a Route that sends every metric
+// matching pattern to one of destinations, chosen by consistent hashing on
+// the metric name. A nil pattern matches every metric.
+func NewConsistentHashingRoute(pattern *regexp.Regexp, destinations ...*Destination) *ConsistentHashingRoute {
+	return &ConsistentHashingRoute{
+		baseRoute: baseRoute{pattern: pattern, destinations: destinations},
+		ring:      newHashRing(destinations),
+	}
+}
+
+// Dispatch implements Route.
+func (r *ConsistentHashingRoute) Dispatch(
+	ctx context.Context,
+	metric graphite.MetricMetadata,
+	value string,
+	timestamp time.Time,
+) error {
+	name := strings.Join(metric.Name, ".")
+
+	if !r.Match(name) {
+		return nil
+	}
+
+	dest := r.ring.Get(name)
+	if dest == nil {
+		return fmt.Errorf("router: no destinations configured for consistent hashing route")
+	}
+
+	if err := dest.SendMetric(ctx, metric, value, timestamp); err != nil {
+		return fmt.Errorf("router: failed to send to %q: %w", dest.Addr(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package router implements a carbon-relay-ng-style routing table: incoming
+// metrics are blacklist-filtered, rewritten and optionally aggregated
+// before being fanned out across one or more Routes, each backed by one or
+// more Destinations.
+package router
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Crystalix007/go-graphite/graphite"
+	"golang.org/x/sync/errgroup"
+)
+
+// Table accepts metrics via SendMetric and dispatches them across its
+// configured Routes, after blacklist filtering, name rewriting and
+// aggregation.
+type Table struct {
+	routes      []Route
+	rewriters   []Rewriter
+	aggregators []*Aggregator
+	blacklist   []*regexp.Regexp
+}
+
+// NewTable creates an empty routing Table.
+func NewTable() *Table {
+	return &Table{}
+}
+
+// AddRoute adds a Route metrics may be dispatched to.
+func (t *Table) AddRoute(route Route) {
+	t.routes = append(t.routes, route)
+}
+
+// AddRewriter adds a name-rewriting rule, applied in the order added.
+func (t *Table) AddRewriter(rewriter Rewriter) {
+	t.rewriters = append(t.rewriters, rewriter)
+}
+
+// AddAggregator adds an aggregation rule. A metric consumed by an
+// Aggregator is not also dispatched to any Route.
+func (t *Table) AddAggregator(aggregator *Aggregator) {
+	t.aggregators = append(t.aggregators, aggregator)
+}
+
+// AddBlacklist adds a pattern: metrics whose dotted name matches it are
+// dropped before reaching any rewriter, aggregator or route.
+func (t *Table) AddBlacklist(pattern *regexp.Regexp) {
+	t.blacklist = append(t.blacklist, pattern)
+}
+
+// SendMetric implements the same signature as graphite.Client.SendMetric,
+// so a Table can be used as a drop-in collectors.MetricSink or nested
+// inside another Table.
+func (t *Table) SendMetric(
+	ctx context.Context,
+	metric graphite.MetricMetadata,
+	value string,
+	timestamp time.Time,
+) error {
+	name := strings.Join(metric.Name, ".")
+
+	for _, pattern := range t.blacklist {
+		if pattern.MatchString(name) {
+			return nil
+		}
+	}
+
+	for _, rewriter := range t.rewriters {
+		metric = rewriter.Rewrite(metric)
+	}
+
+	for _, aggregator := range t.aggregators {
+		if aggregator.Add(metric, value) {
+			return nil
+		}
+	}
+
+	for _, route := range t.routes {
+		if err := route.Dispatch(ctx, metric, value, timestamp); err != nil {
+			return fmt.Errorf("router: failed to dispatch metric: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Run starts every Destination's writer loop and every Aggregator's flush
+// loop, and blocks until ctx is cancelled or one of them fails.
+func (t *Table) Run(ctx context.Context) error {
+	errg, ctx := errgroup.WithContext(ctx)
+
+	started := make(map[*Destination]struct{})
+
+	runDestination := func(dest *Destination) {
+		if _, ok := started[dest]; ok {
+			return
+		}
+
+		started[dest] = struct{}{}
+
+		errg.Go(func() error {
+			return dest.Run(ctx)
+		})
+	}
+
+	for _, route := range t.routes {
+		for _, dest := range route.Destinations() {
+			runDestination(dest)
+		}
+	}
+
+	for _, aggregator := range t.aggregators {
+		aggregator := aggregator
+
+		errg.Go(func() error {
+			return aggregator.Run(ctx)
+		})
+
+		for _, dest := range aggregator.Destinations {
+			runDestination(dest)
+		}
+	}
+
+	return errg.Wait()
+}
@@ -0,0 +1,226 @@
+package graphite
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// spoolSegmentMaxBytes is the size at which a disk spool rotates to a new
+// segment file.
+const spoolSegmentMaxBytes = 1 << 20 // 1 MiB
+
+// spoolRecord is the on-disk representation of a single spooled metric.
+type spoolRecord struct {
+	Name      []string          `json:"name"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Value     string            `json:"value"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// diskSpool persists metrics that couldn't be queued to append-only
+// segment files, for FIFO replay once the client has a working connection
+// again. It is bounded to maxBytes total, evicting its oldest segment once
+// exceeded.
+type diskSpool struct {
+	dir      string
+	maxBytes int64
+
+	mu         sync.Mutex
+	segments   []string // ordered oldest-first, basenames under dir
+	active     *os.File
+	activeSize int64
+	totalSize  int64
+}
+
+func newDiskSpool(dir string, maxBytes int64) (*diskSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("graphite: failed to create spool directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("graphite: failed to read spool directory: %w", err)
+	}
+
+	spool := &diskSpool{dir: dir, maxBytes: maxBytes}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+
+		spool.segments = append(spool.segments, entry.Name())
+
+		if info, err := entry.Info(); err == nil {
+			spool.totalSize += info.Size()
+		}
+	}
+
+	sort.Strings(spool.segments)
+
+	return spool, nil
+}
+
+// Write appends metric to the active segment, rotating and evicting as
+// needed to respect maxBytes.
+func (s *diskSpool) Write(metric Metric) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active == nil || s.activeSize >= spoolSegmentMaxBytes {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	record := spoolRecord{
+		Name:      metric.Name,
+		Tags:      metric.Tags,
+		Value:     metric.Value,
+		Timestamp: metric.Timestamp,
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("graphite: failed to marshal spooled metric: %w", err)
+	}
+
+	line = append(line, '\n')
+
+	n, err := s.active.Write(line)
+	if err != nil {
+		return fmt.Errorf("graphite: failed to write spooled metric: %w", err)
+	}
+
+	s.activeSize += int64(n)
+	s.totalSize += int64(n)
+
+	s.evictLocked()
+
+	return nil
+}
+
+func (s *diskSpool) rotateLocked() error {
+	if s.active != nil {
+		s.active.Close()
+	}
+
+	name := fmt.Sprintf("%020d.jsonl", time.Now().UnixNano())
+
+	file, err := os.OpenFile(
+		filepath.Join(s.dir, name),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0o644,
+	)
+	if err != nil {
+		return fmt.Errorf("graphite: failed to create spool segment: %w", err)
+	}
+
+	s.active = file
+	s.activeSize = 0
+	s.segments = append(s.segments, name)
+
+	return nil
+}
+
+// evictLocked removes the oldest segments until totalSize is within
+// maxBytes, always keeping at least the active segment.
+func (s *diskSpool) evictLocked() {
+	if s.maxBytes <= 0 {
+		return
+	}
+
+	for s.totalSize > s.maxBytes && len(s.segments) > 1 {
+		oldest := s.segments[0]
+
+		if info, err := os.Stat(filepath.Join(s.dir, oldest)); err == nil {
+			s.totalSize -= info.Size()
+		}
+
+		os.Remove(filepath.Join(s.dir, oldest))
+		s.segments = s.segments[1:]
+	}
+}
+
+// Replay reads spooled metrics oldest segment first and calls send with
+// each segment's metrics in FIFO order. A segment is only removed from disk
+// once send returns nil for it, so a failure (e.g. the connection drops
+// again mid-replay) leaves that segment and every later one in place for
+// the next Replay call, instead of deleting metrics that were read but
+// never confirmed sent.
+func (s *diskSpool) Replay(send func(metrics []Metric) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active != nil {
+		s.active.Close()
+		s.active = nil
+		s.activeSize = 0
+	}
+
+	for len(s.segments) > 0 {
+		name := s.segments[0]
+		path := filepath.Join(s.dir, name)
+
+		metrics, err := readSpoolSegment(path)
+		if err != nil {
+			return err
+		}
+
+		if err := send(metrics); err != nil {
+			return err
+		}
+
+		if info, err := os.Stat(path); err == nil {
+			s.totalSize -= info.Size()
+		}
+
+		os.Remove(path)
+
+		s.segments = s.segments[1:]
+	}
+
+	return nil
+}
+
+func readSpoolSegment(path string) ([]Metric, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("graphite: failed to open spool segment %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var metrics []Metric
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	for scanner.Scan() {
+		var record spoolRecord
+
+		// A partially-written final line (e.g. after a crash mid-append)
+		// is skipped rather than failing the whole replay.
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+
+		metrics = append(metrics, Metric{
+			MetricMetadata: MetricMetadata{Name: record.Name, Tags: record.Tags},
+			Value:          record.Value,
+			Timestamp:      record.Timestamp,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("graphite: failed to read spool segment %s: %w", path, err)
+	}
+
+	return metrics, nil
+}